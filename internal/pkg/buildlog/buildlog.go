@@ -2,6 +2,7 @@ package buildlog
 
 import (
 	"bufio"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -43,6 +44,24 @@ func (p BuildPhase) String() string {
 	}[p]
 }
 
+func normalizePhaseName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name)
+}
+
+// ParsePhase returns the build phase for the given name. The name can be
+// written as in String() or as the constant name, case is ignored.
+func ParsePhase(name string) (BuildPhase, error) {
+	var valid []string
+	for p := Header; p <= Unknown; p++ {
+		if normalizePhaseName(p.String()) == normalizePhaseName(name) {
+			return p, nil
+		}
+		valid = append(valid, p.String())
+	}
+	return Unknown, fmt.Errorf("unknown build phase '%s', valid phases are: %s", name, strings.Join(valid, ", "))
+}
+
 type Phase struct {
 	Type      BuildPhase
 	Succeeded bool
@@ -51,12 +70,13 @@ type Phase struct {
 }
 
 type BuildLog struct {
-	Name    string
-	Project string
-	Distro  string
-	Arch    string
-	Phases  []Phase
-	rawlog  string
+	Name      string
+	Project   string
+	Distro    string
+	Arch      string
+	Phases    []Phase
+	Diagnosis *Diagnosis
+	rawlog    string
 }
 
 var (
@@ -157,19 +177,12 @@ func Parse(logContent string) *BuildLog {
 	currentPhaseDetails.Duration = lastTime - phaseStartTime
 	currentPhaseDetails.Succeeded = (currentPhaseDetails.Type == Summary && !hasError)
 	log.Phases = append(log.Phases, currentPhaseDetails)
+	log.Diagnosis = log.diagnose()
 
 	return log
 }
 
-func (log *BuildLog) FormatJson(nrLines int, offset int, printSucceded bool, match, exclude string) map[string]any {
-	properties := map[string]string{
-		"Name":    log.Name,
-		"Project": log.Project,
-		"Distro":  log.Distro,
-		"Arch":    log.Arch,
-	}
-
-	var matchRe, excludeRe *regexp.Regexp
+func compileFilters(match, exclude string) (matchRe, excludeRe *regexp.Regexp) {
 	var err error
 	if match != "" {
 		matchRe, err = regexp.Compile(match)
@@ -183,24 +196,131 @@ func (log *BuildLog) FormatJson(nrLines int, offset int, printSucceded bool, mat
 			excludeRe = nil
 		}
 	}
+	return matchRe, excludeRe
+}
 
-	phases := []any{}
-	for _, phaseDetails := range log.Phases {
-		lines := phaseDetails.Lines
-		var filteredLines []string
-		if match != "" || exclude != "" {
-			for _, line := range lines {
-				if excludeRe != nil && excludeRe.MatchString(line) {
-					continue
-				}
-				if matchRe != nil && !matchRe.MatchString(line) {
-					continue
-				}
-				filteredLines = append(filteredLines, line)
+func filterLines(lines []string, matchRe, excludeRe *regexp.Regexp) []string {
+	if matchRe == nil && excludeRe == nil {
+		return lines
+	}
+	var filteredLines []string
+	for _, line := range lines {
+		if excludeRe != nil && excludeRe.MatchString(line) {
+			continue
+		}
+		if matchRe != nil && !matchRe.MatchString(line) {
+			continue
+		}
+		filteredLines = append(filteredLines, line)
+	}
+	return filteredLines
+}
+
+// PhaseByType returns the phase of the given type. The phases stay a slice,
+// as their order is the order of the log.
+func (log *BuildLog) PhaseByType(phaseType BuildPhase) (Phase, bool) {
+	for _, phase := range log.Phases {
+		if phase.Type == phaseType {
+			return phase, true
+		}
+	}
+	return Phase{}, false
+}
+
+// LintFinding is an error, warning or informational message of rpmlint.
+type LintFinding struct {
+	Package  string `json:"package"`
+	Line     string `json:"line,omitempty"`
+	Severity string `json:"severity" jsonschema:"E for errors, W for warnings and I for informational messages"`
+	Check    string `json:"check"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// rpmLintRegex matches lines like
+//
+//	foo.x86_64: W: no-manual-page-for-binary foo
+//	foo.spec:12: E: specfile-error error: line 12: Unknown tag
+var rpmLintRegex = regexp.MustCompile(`^(\S+?):(?:(\d+):)?\s+([EWI]):\s+(\S+)\s*(.*)$`)
+
+// ParseRpmLint returns the findings in the output of rpmlint. Other lines,
+// like the explanations following the findings, are skipped.
+func ParseRpmLint(lines []string) []LintFinding {
+	findings := []LintFinding{}
+	for _, line := range lines {
+		matches := rpmLintRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Package:  matches[1],
+			Line:     matches[2],
+			Severity: matches[3],
+			Check:    matches[4],
+			Detail:   matches[5],
+		})
+	}
+	return findings
+}
+
+// RpmLint returns the findings of the rpmlint report of the log.
+func (log *BuildLog) RpmLint() []LintFinding {
+	phase, ok := log.PhaseByType(RPMLintReport)
+	if !ok {
+		return []LintFinding{}
+	}
+	return ParseRpmLint(phase.Lines)
+}
+
+// SelectPhases returns a copy of the log which only contains the given phases.
+func (log *BuildLog) SelectPhases(phases []BuildPhase) *BuildLog {
+	selected := *log
+	selected.Phases = []Phase{}
+	for _, phase := range log.Phases {
+		for _, p := range phases {
+			if phase.Type == p {
+				selected.Phases = append(selected.Phases, phase)
+				break
 			}
-		} else {
-			filteredLines = lines
 		}
+	}
+	return &selected
+}
+
+// pageLines returns at most nrLines lines starting at offset. If offset is 0
+// the last nrLines lines are returned. The returned start is the offset of the
+// first returned line.
+func pageLines(lines []string, nrLines, offset int) (start int, page []string) {
+	total := len(lines)
+	start = offset
+	if start == 0 {
+		start = total - nrLines
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + nrLines
+	if end > total {
+		end = total
+	}
+	return start, lines[start:end]
+}
+
+func (log *BuildLog) FormatJson(nrLines int, offset int, printSucceded bool, match, exclude string) map[string]any {
+	properties := map[string]string{
+		"Name":    log.Name,
+		"Project": log.Project,
+		"Distro":  log.Distro,
+		"Arch":    log.Arch,
+	}
+
+	matchRe, excludeRe := compileFilters(match, exclude)
+
+	phases := []any{}
+	for _, phaseDetails := range log.Phases {
+		filteredLines := filterLines(phaseDetails.Lines, matchRe, excludeRe)
 
 		phaseData := map[string]any{
 			"Phase":    phaseDetails.Type.String(),
@@ -215,25 +335,60 @@ func (log *BuildLog) FormatJson(nrLines int, offset int, printSucceded bool, mat
 			if !printSucceded && phaseDetails.Succeeded && match == "" && exclude == "" {
 				// don't add lines
 			} else if len(filteredLines) > 0 {
-				currentOffset := offset
-				printLines := nrLines
-				if currentOffset+printLines > len(filteredLines) {
-					printLines = len(filteredLines) - currentOffset
-				}
-				if currentOffset == 0 {
-					currentOffset = len(filteredLines) - printLines
-					if currentOffset < 0 {
-						currentOffset = 0
-					}
-				}
-				phaseData["Lines"] = filteredLines[currentOffset:len(filteredLines)]
+				_, phaseData["Lines"] = pageLines(filteredLines, nrLines, offset)
 			}
 		}
 		phases = append(phases, phaseData)
 	}
 
-	return map[string]any{
+	result := map[string]any{
 		"Properties": properties,
 		"Phases":     phases,
 	}
+	if log.Diagnosis != nil {
+		result["Diagnosis"] = log.Diagnosis
+	}
+	return result
+}
+
+// FormatTail flattens the lines of all phases which didn't succeed into a
+// single stream and applies offset and nrLines to that stream. If offset is
+// 0 the last nrLines lines are returned. TotalLines holds the number of lines
+// in the stream, so that the caller can page through it.
+func (log *BuildLog) FormatTail(nrLines int, offset int, printSucceded bool, match, exclude string) map[string]any {
+	properties := map[string]string{
+		"Name":    log.Name,
+		"Project": log.Project,
+		"Distro":  log.Distro,
+		"Arch":    log.Arch,
+	}
+
+	matchRe, excludeRe := compileFilters(match, exclude)
+
+	var lines []string
+	phaseNames := []string{}
+	for _, phaseDetails := range log.Phases {
+		if phaseDetails.Succeeded && !printSucceded {
+			continue
+		}
+		filteredLines := filterLines(phaseDetails.Lines, matchRe, excludeRe)
+		if len(filteredLines) == 0 {
+			continue
+		}
+		phaseNames = append(phaseNames, phaseDetails.Type.String())
+		lines = append(lines, filteredLines...)
+	}
+
+	start, page := pageLines(lines, nrLines, offset)
+	result := map[string]any{
+		"Properties": properties,
+		"Phases":     phaseNames,
+		"TotalLines": len(lines),
+		"Offset":     start,
+		"Lines":      page,
+	}
+	if log.Diagnosis != nil {
+		result["Diagnosis"] = log.Diagnosis
+	}
+	return result
 }
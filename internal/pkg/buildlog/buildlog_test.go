@@ -1,7 +1,9 @@
 package buildlog
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,12 +82,172 @@ func TestParseLog(t *testing.T) {
 
 			assert.Equal(t, len(tc.expectedPhases), len(log.Phases))
 
-			for _, actual := range log.Phases {
-				expected, ok := tc.expectedPhases[actual.Type]
-				assert.True(t, ok, "Unexpected phase %s", actual.Type.String())
-				assert.Equal(t, expected.lineCount, len(actual.Lines), "Line count mismatch for phase %s", actual.Type.String())
-				assert.Equal(t, expected.duration, actual.Duration, "Duration mismatch for phase %s", actual.Type.String())
+			for phaseType, expected := range tc.expectedPhases {
+				actual, ok := log.PhaseByType(phaseType)
+				if !assert.True(t, ok, "Missing phase %s", phaseType.String()) {
+					continue
+				}
+				assert.Equal(t, expected.lineCount, len(actual.Lines), "Line count mismatch for phase %s", phaseType.String())
+				assert.Equal(t, expected.duration, actual.Duration, "Duration mismatch for phase %s", phaseType.String())
 			}
 		})
 	}
 }
+
+func TestFormatTail(t *testing.T) {
+	log := &BuildLog{
+		Name: "foo",
+		Phases: []Phase{
+			{Type: Header, Succeeded: true, Lines: []string{"h1", "h2"}},
+			{Type: Build, Succeeded: false, Lines: []string{"b1", "b2", "b3"}},
+			{Type: PostBuildChecks, Succeeded: true, Lines: []string{"p1"}},
+			{Type: Summary, Succeeded: false, Lines: []string{"s1", "s2"}},
+		},
+	}
+
+	result := log.FormatTail(2, 0, false, "", "")
+	assert.Equal(t, 5, result["TotalLines"])
+	assert.Equal(t, 3, result["Offset"])
+	assert.Equal(t, []string{"s1", "s2"}, result["Lines"])
+	assert.Equal(t, []string{"Build", "Summary"}, result["Phases"])
+
+	result = log.FormatTail(3, 2, false, "", "")
+	assert.Equal(t, []string{"b3", "s1", "s2"}, result["Lines"])
+
+	result = log.FormatTail(10, 4, false, "", "")
+	assert.Equal(t, []string{"s2"}, result["Lines"])
+
+	result = log.FormatTail(10, 0, true, "", "^[hs]")
+	assert.Equal(t, 4, result["TotalLines"])
+	assert.Equal(t, []string{"b1", "b2", "b3", "p1"}, result["Lines"])
+}
+
+func TestFormatJsonPaging(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	log := &BuildLog{
+		Name: "foo",
+		Phases: []Phase{
+			{Type: Build, Succeeded: false, Lines: lines},
+		},
+	}
+	phaseLines := func(result map[string]any) any {
+		return result["Phases"].([]any)[0].(map[string]any)["Lines"]
+	}
+
+	// tail
+	result := log.FormatJson(3, 0, false, "", "")
+	assert.Equal(t, []string{"line 7", "line 8", "line 9"}, phaseLines(result))
+
+	// paged
+	result = log.FormatJson(3, 2, false, "", "")
+	assert.Equal(t, []string{"line 2", "line 3", "line 4"}, phaseLines(result))
+
+	result = log.FormatJson(3, 8, false, "", "")
+	assert.Equal(t, []string{"line 8", "line 9"}, phaseLines(result))
+
+	result = log.FormatJson(3, 20, false, "", "")
+	assert.Equal(t, []string{}, phaseLines(result))
+}
+
+func TestSelectPhases(t *testing.T) {
+	phase, err := ParsePhase("RPMLintReport")
+	assert.NoError(t, err)
+	assert.Equal(t, RPMLintReport, phase)
+	phase, err = ParsePhase("post build checks")
+	assert.NoError(t, err)
+	assert.Equal(t, PostBuildChecks, phase)
+	_, err = ParsePhase("compile")
+	assert.ErrorContains(t, err, "RPM lint report")
+
+	log := &BuildLog{
+		Name: "foo",
+		Phases: []Phase{
+			{Type: Header, Succeeded: true, Lines: []string{"h1"}},
+			{Type: Build, Succeeded: true, Lines: []string{"b1"}},
+			{Type: RPMLintReport, Succeeded: false, Lines: []string{"r1"}},
+		},
+	}
+	selected := log.SelectPhases([]BuildPhase{Build, RPMLintReport})
+	assert.Len(t, selected.Phases, 2)
+	assert.Len(t, log.Phases, 3)
+	result := selected.FormatTail(10, 0, true, "", "")
+	assert.Equal(t, []string{"b1", "r1"}, result["Lines"])
+}
+
+func TestFormatJsonFilter(t *testing.T) {
+	log := &BuildLog{
+		Name: "foo",
+		Phases: []Phase{
+			{Type: Header, Succeeded: true, Lines: []string{"[   1s] header"}},
+			{Type: Build, Succeeded: false, Lines: []string{
+				"[  10s] compiling foo.c",
+				"[  11s] warning: unused variable",
+				"[  12s] compiling bar.c",
+				"[  13s] error: undefined reference",
+				"[  14s] warning: implicit declaration",
+			}},
+		},
+	}
+	phase := func(result map[string]any, i int) map[string]any {
+		return result["Phases"].([]any)[i].(map[string]any)
+	}
+
+	// with a filter the matching lines of succeeded phases are shown, too
+	result := log.FormatJson(10, 0, false, "", "warning:")
+	assert.Equal(t, []string{"[   1s] header"}, phase(result, 0)["Lines"])
+	assert.Equal(t, 3, phase(result, 1)["NrLines"])
+	assert.Equal(t, []string{"[  10s] compiling foo.c", "[  12s] compiling bar.c", "[  13s] error: undefined reference"}, phase(result, 1)["Lines"])
+
+	// the filter is applied before the lines are paged
+	result = log.FormatJson(1, 0, false, "compiling|error", "foo")
+	assert.Equal(t, []string{"[  13s] error: undefined reference"}, phase(result, 1)["Lines"])
+	result = log.FormatJson(1, 1, false, "compiling|error", "foo")
+	assert.Equal(t, []string{"[  13s] error: undefined reference"}, phase(result, 1)["Lines"])
+	result = log.FormatJson(1, 0, false, "error", "")
+	assert.Equal(t, []string{"[  13s] error: undefined reference"}, phase(result, 1)["Lines"])
+}
+
+func TestRpmLint(t *testing.T) {
+	content, err := os.ReadFile("testdata/gflags_aarch64.log")
+	assert.NoError(t, err)
+	entries := Parse(string(content)).RpmLint()
+	assert.Equal(t, []LintFinding{{Package: "gflags.aarch64", Severity: "E", Check: "no-binary"}}, entries)
+
+	content, err = os.ReadFile("testdata/local-ww4.log")
+	assert.NoError(t, err)
+	entries = Parse(string(content)).RpmLint()
+	assert.NotEmpty(t, entries)
+	assert.Equal(t, LintFinding{
+		Package:  "warewulf4-overlay.x86_64",
+		Severity: "W",
+		Check:    "hidden-file-or-dir",
+		Detail:   "/var/lib/warewulf/overlays/ssh.authorized_keys/rootfs/root/.ssh",
+	}, entries[0])
+	assert.Contains(t, entries, LintFinding{Package: "warewulf4.src", Line: "205", Severity: "W", Check: "macro-in-comment", Detail: "%{buildroot}"})
+
+	assert.Empty(t, Parse("[    1s] no report\n").RpmLint())
+}
+
+func TestParseRpmLint(t *testing.T) {
+	output := `============================ rpmlint session starts ============================
+rpmlint: 2.5.0
+checks: 32, packages: 2
+
+foo.spec:12: W: macro-in-comment %install
+foo.x86_64: E: zero-length /usr/share/doc/packages/foo/README
+foo.x86_64: W: no-manual-page-for-binary foo
+foo-debuginfo.x86_64: I: no-debuginfo
+
+ 2 packages and 1 specfiles checked; 1 errors, 2 warnings, 0 badness; has taken 0.5 s
+`
+	assert.Equal(t, []LintFinding{
+		{Package: "foo.spec", Line: "12", Severity: "W", Check: "macro-in-comment", Detail: "%install"},
+		{Package: "foo.x86_64", Severity: "E", Check: "zero-length", Detail: "/usr/share/doc/packages/foo/README"},
+		{Package: "foo.x86_64", Severity: "W", Check: "no-manual-page-for-binary", Detail: "foo"},
+		{Package: "foo-debuginfo.x86_64", Severity: "I", Check: "no-debuginfo"},
+	}, ParseRpmLint(strings.Split(output, "\n")))
+	assert.Empty(t, ParseRpmLint([]string{"rpmlint: 2.5.0"}))
+}
@@ -0,0 +1,52 @@
+package buildlog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Diagnosis is a well-known cause of a failed build together with a hint how
+// to fix it.
+type Diagnosis struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Line    string `json:"line" jsonschema:"The log line which matched"`
+}
+
+var diagnoses = []struct {
+	code    string
+	message string
+	matcher *regexp.Regexp
+}{
+	{"no_space", "the build ran out of disk space, increase the disk of the build root or clean up old build roots",
+		regexp.MustCompile(`No space left on device`)},
+	{"out_of_memory", "a process of the build was killed, most likely because the build ran out of memory, reduce the parallel jobs or increase the memory",
+		regexp.MustCompile(`(?i)out of memory|oom-kill|\bKilled\b`)},
+	{"unresolvable", "the build dependencies can't be resolved, check the missing packages and the repositories of the project",
+		regexp.MustCompile(`unresolvable|nothing provides`)},
+	{"missing_buildrequires", "a dependency of the build is missing, add the matching BuildRequires to the spec file",
+		regexp.MustCompile(`Failed build dependencies|is needed by|No package '\S+' found|fatal error: \S+: No such file or directory|Could not find a package configuration file`)},
+	{"file_not_found", "a file listed in %files wasn't installed, fix the %files section or the %install section",
+		regexp.MustCompile(`File not found( by glob)?:`)},
+	{"install_failed", "the installation of the packages into the build root failed, check the build dependencies",
+		regexp.MustCompile(`install failed`)},
+}
+
+// diagnose scans the lines of the failed phases for well-known causes of a
+// failed build. The signatures are checked in order, so that a full disk
+// isn't reported as a missing file.
+func (log *BuildLog) diagnose() *Diagnosis {
+	for _, d := range diagnoses {
+		for _, phase := range log.Phases {
+			if phase.Succeeded {
+				continue
+			}
+			for _, line := range phase.Lines {
+				if d.matcher.MatchString(line) {
+					return &Diagnosis{Code: d.code, Message: d.message, Line: strings.TrimSpace(line)}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package buildlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnose(t *testing.T) {
+	testCases := []struct {
+		name string
+		line string
+		code string
+	}{
+		{"no space", "[  120s] cc1plus: fatal error: error writing to /tmp/ccX.s: No space left on device", "no_space"},
+		{"killed", "[  300s] g++: fatal error: Killed signal terminated program cc1plus", "out_of_memory"},
+		{"oom", "[  300s] [ 1234.5] Out of memory: Killed process 4242 (cc1plus)", "out_of_memory"},
+		{"unresolvable", "[    5s] unresolvable: nothing provides libfoo-devel", "unresolvable"},
+		{"failed build dependencies", "[   10s] error: Failed build dependencies:", "missing_buildrequires"},
+		{"pkg-config", "[   30s] No package 'glib-2.0' found", "missing_buildrequires"},
+		{"missing header", "[   40s] foo.c:3:10: fatal error: zlib.h: No such file or directory", "missing_buildrequires"},
+		{"file not found by glob", "[   60s]     File not found by glob: /home/abuild/rpmbuild/BUILDROOT/foo-1.0-0.x86_64/usr/share/man/man1/foo.1*", "file_not_found"},
+		{"file not found", "[   60s]     File not found: /home/abuild/rpmbuild/BUILDROOT/foo-1.0-0.x86_64/usr/bin/foo", "file_not_found"},
+		{"install failed", "[    8s] install failed for package glibc", "install_failed"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := Parse("[    1s] started \"build foo.spec\"\n" +
+				"[    2s] -----------------------------------------------------------------\n" +
+				tc.line + "\n")
+			if assert.NotNil(t, log.Diagnosis) {
+				assert.Equal(t, tc.code, log.Diagnosis.Code)
+				assert.NotEmpty(t, log.Diagnosis.Message)
+				assert.Contains(t, tc.line, log.Diagnosis.Line)
+			}
+			assert.Equal(t, log.Diagnosis, log.FormatJson(10, 0, false, "", "")["Diagnosis"])
+			assert.Equal(t, log.Diagnosis, log.FormatTail(10, 0, false, "", "")["Diagnosis"])
+		})
+	}
+}
+
+func TestDiagnoseSucceededPhase(t *testing.T) {
+	log := &BuildLog{Phases: []Phase{
+		{Type: Build, Succeeded: true, Lines: []string{"No space left on device"}},
+		{Type: Summary, Succeeded: false, Lines: []string{"failed"}},
+	}}
+	assert.Nil(t, log.diagnose())
+}
@@ -0,0 +1,148 @@
+package osc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// BinaryList is the listing of the binaries of a build, which the api
+// returns instead of a Directory for the build results.
+type BinaryList struct {
+	XMLName  xml.Name      `xml:"binarylist"`
+	Package  string        `xml:"package,attr,omitempty"`
+	Binaries []BinaryEntry `xml:"binary"`
+}
+
+type BinaryEntry struct {
+	XMLName  xml.Name `xml:"binary"`
+	Filename string   `xml:"filename,attr"`
+	Size     string   `xml:"size,attr"`
+	Mtime    string   `xml:"mtime,attr"`
+}
+
+type ListBuildResultsParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle"`
+	Repository  string `json:"repository" jsonschema:"Repository of the build, e.g. openSUSE_Tumbleweed"`
+	Arch        string `json:"arch" jsonschema:"Architecture of the build, e.g. x86_64"`
+}
+
+type BuiltBinary struct {
+	Name  string `json:"name"`
+	Size  string `json:"size"`
+	Mtime string `json:"mtime,omitempty"`
+}
+
+type ListBuildResultsResult struct {
+	ProjectName string        `json:"project_name"`
+	PackageName string        `json:"package_name"`
+	Repository  string        `json:"repository"`
+	Arch        string        `json:"arch"`
+	Binaries    []BuiltBinary `json:"binaries" jsonschema:"The binaries of the last successful build, including the rpms and the build logs"`
+}
+
+type DownloadBinaryParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle"`
+	Repository  string `json:"repository" jsonschema:"Repository of the build"`
+	Arch        string `json:"arch" jsonschema:"Architecture of the build"`
+	Binary      string `json:"binary" jsonschema:"File name of the binary as returned by list_binaries"`
+}
+
+type DownloadBinaryResult struct {
+	Path string `json:"path" jsonschema:"Local path of the downloaded binary"`
+	Size int64  `json:"size"`
+}
+
+// buildResultPath returns the api path of the binaries of a build.
+func buildResultPath(projectName, repository, arch, packageName string) string {
+	return fmt.Sprintf("build/%s/%s/%s/%s", projectName, repository, arch, packageName)
+}
+
+func checkBuildResultParams(projectName, packageName, repository, arch string) error {
+	if projectName == "" || packageName == "" {
+		return fmt.Errorf("project and package name cannot be empty")
+	}
+	if repository == "" || arch == "" {
+		return fmt.Errorf("repository and arch cannot be empty")
+	}
+	// the names are also used as directories below the TempDir
+	for _, name := range []string{projectName, packageName, repository, arch} {
+		if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid name: %s", name)
+		}
+	}
+	return nil
+}
+
+func (cred *OSCCredentials) ListBuildResults(ctx context.Context, req *mcp.CallToolRequest, params ListBuildResultsParam) (*mcp.CallToolResult, *ListBuildResultsResult, error) {
+	slog.Debug("mcp tool call: ListBuildResults", "params", params)
+	if err := checkBuildResultParams(params.ProjectName, params.PackageName, params.Repository, params.Arch); err != nil {
+		return nil, nil, err
+	}
+	resp, err := cred.apiGetRequest(ctx, buildResultPath(params.ProjectName, params.Repository, params.Arch, params.PackageName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, nil, fmt.Errorf("api request failed with status: %s\nbody:\n%s", resp.Status, string(body))
+	}
+
+	var list BinaryList
+	if err := xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse binary list: %w", err)
+	}
+	result := &ListBuildResultsResult{
+		ProjectName: params.ProjectName,
+		PackageName: params.PackageName,
+		Repository:  params.Repository,
+		Arch:        params.Arch,
+		Binaries:    []BuiltBinary{},
+	}
+	for _, binary := range list.Binaries {
+		result.Binaries = append(result.Binaries, BuiltBinary{
+			Name:  binary.Filename,
+			Size:  binary.Size,
+			Mtime: binary.Mtime,
+		})
+	}
+	return nil, result, nil
+}
+
+func (cred *OSCCredentials) DownloadBinary(ctx context.Context, req *mcp.CallToolRequest, params DownloadBinaryParam) (*mcp.CallToolResult, *DownloadBinaryResult, error) {
+	slog.Debug("mcp tool call: DownloadBinary", "params", params)
+	if err := checkBuildResultParams(params.ProjectName, params.PackageName, params.Repository, params.Arch); err != nil {
+		return nil, nil, err
+	}
+	if params.Binary == "" || params.Binary != filepath.Base(params.Binary) || strings.HasPrefix(params.Binary, ".") {
+		return nil, nil, fmt.Errorf("invalid binary name: %s", params.Binary)
+	}
+
+	dir := filepath.Join(cred.TempDir, "binaries", params.ProjectName, params.Repository, params.Arch, params.PackageName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	destination := filepath.Join(dir, params.Binary)
+	url := fmt.Sprintf("%s/%s/%s", cred.GetAPiAddr(), buildResultPath(params.ProjectName, params.Repository, params.Arch, params.PackageName), params.Binary)
+	if err := cred.downloadURL(ctx, url, destination); err != nil {
+		return nil, nil, fmt.Errorf("failed to download %s: %w", params.Binary, err)
+	}
+	info, err := os.Stat(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &DownloadBinaryResult{Path: destination, Size: info.Size()}, nil
+}
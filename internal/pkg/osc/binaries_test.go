@@ -0,0 +1,64 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildResultBinaries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/build/home:testuser/openSUSE_Tumbleweed/x86_64/foo":
+			fmt.Fprint(w, `<binarylist package="foo">
+  <binary filename="_buildenv" size="12345" mtime="1700000000"/>
+  <binary filename="foo-1.0-1.1.x86_64.rpm" size="4" mtime="1700000000"/>
+</binarylist>`)
+		case "/build/home:testuser/openSUSE_Tumbleweed/x86_64/foo/foo-1.0-1.1.x86_64.rpm":
+			fmt.Fprint(w, "\xed\xab\xee\xdb")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, TempDir: t.TempDir()}
+	_, list, err := cred.ListBuildResults(context.Background(), nil, ListBuildResultsParam{ProjectName: "home:testuser", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: "x86_64"})
+	assert.NoError(t, err)
+	assert.Equal(t, []BuiltBinary{
+		{Name: "_buildenv", Size: "12345", Mtime: "1700000000"},
+		{Name: "foo-1.0-1.1.x86_64.rpm", Size: "4", Mtime: "1700000000"},
+	}, list.Binaries)
+
+	_, _, err = cred.ListBuildResults(context.Background(), nil, ListBuildResultsParam{ProjectName: "home:testuser", PackageName: "bar", Repository: "openSUSE_Tumbleweed", Arch: "x86_64"})
+	assert.ErrorIs(t, err, ErrBundleOrProjectNotFound)
+
+	_, result, err := cred.DownloadBinary(context.Background(), nil, DownloadBinaryParam{ProjectName: "home:testuser", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: "x86_64", Binary: "foo-1.0-1.1.x86_64.rpm"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.Size)
+	content, err := os.ReadFile(result.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, "\xed\xab\xee\xdb", string(content))
+
+	_, _, err = cred.DownloadBinary(context.Background(), nil, DownloadBinaryParam{ProjectName: "home:testuser", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: "x86_64", Binary: "../../foo.rpm"})
+	assert.ErrorContains(t, err, "invalid binary name")
+
+	for _, params := range []DownloadBinaryParam{
+		{ProjectName: "../../..", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: "x86_64"},
+		{ProjectName: "home:testuser", PackageName: "..", Repository: "openSUSE_Tumbleweed", Arch: "x86_64"},
+		{ProjectName: "home:testuser", PackageName: "foo", Repository: "../tmp", Arch: "x86_64"},
+		{ProjectName: "home:testuser", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: `..\x86_64`},
+	} {
+		params.Binary = "foo-1.0-1.1.x86_64.rpm"
+		_, _, err = cred.DownloadBinary(context.Background(), nil, params)
+		assert.ErrorContains(t, err, "invalid name")
+	}
+	entries, err := os.ReadDir(cred.TempDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
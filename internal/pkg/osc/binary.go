@@ -0,0 +1,115 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type BinaryToSourceParam struct {
+	BinaryName string   `json:"binary_name" jsonschema:"Name of the binary package, e.g. libfoo1"`
+	Projects   []string `json:"projects,omitempty" jsonschema:"Optional list of projects to search in"`
+	Arch       string   `json:"arch,omitempty" jsonschema:"Optional architecture of the binary"`
+}
+
+type SourceCandidate struct {
+	ProjectName  string   `json:"project_name"`
+	PackageName  string   `json:"package_name"`
+	Version      string   `json:"version,omitempty"`
+	Repositories []string `json:"repositories"`
+	Arches       []string `json:"arches"`
+}
+
+type BinaryToSourceResult struct {
+	BinaryName string            `json:"binary_name"`
+	Candidates []SourceCandidate `json:"candidates" jsonschema:"Source packages which build the binary, one for each project"`
+}
+
+// binaryMatch builds the xpath match for a published binary.
+func binaryMatch(params BinaryToSourceParam) string {
+	matches := []string{fmt.Sprintf("@name='%s'", params.BinaryName)}
+	if params.Arch != "" {
+		matches = append(matches, fmt.Sprintf("@arch='%s'", params.Arch))
+	}
+	if len(params.Projects) > 0 {
+		var projectMatches []string
+		for _, p := range params.Projects {
+			projectMatches = append(projectMatches, fmt.Sprintf("@project='%s'", p))
+		}
+		matches = append(matches, fmt.Sprintf("(%s)", strings.Join(projectMatches, " or ")))
+	}
+	return strings.Join(matches, " and ")
+}
+
+func (cred *OSCCredentials) BinaryToSource(ctx context.Context, req *mcp.CallToolRequest, params BinaryToSourceParam) (*mcp.CallToolResult, *BinaryToSourceResult, error) {
+	slog.Debug("mcp tool call: BinaryToSource", "params", params)
+	if params.BinaryName == "" {
+		return nil, nil, fmt.Errorf("binary name cannot be empty")
+	}
+	path := "search/published/binary/id?match=" + url.QueryEscape(binaryMatch(params))
+	resp, err := cred.apiGetRequest(ctx, path, map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	candidates := make(map[string]*SourceCandidate)
+	for _, binary := range doc.FindElements("//binary") {
+		// the name is matched exactly, but be safe against prefix matches
+		if binary.SelectAttrValue("name", "") != params.BinaryName {
+			continue
+		}
+		project := binary.SelectAttrValue("project", "")
+		pkg := binary.SelectAttrValue("package", "")
+		key := project + "/" + pkg
+		candidate, ok := candidates[key]
+		if !ok {
+			candidate = &SourceCandidate{
+				ProjectName:  project,
+				PackageName:  pkg,
+				Version:      binary.SelectAttrValue("version", ""),
+				Repositories: []string{},
+				Arches:       []string{},
+			}
+			candidates[key] = candidate
+		}
+		if repo := binary.SelectAttrValue("repository", ""); repo != "" && !slices.Contains(candidate.Repositories, repo) {
+			candidate.Repositories = append(candidate.Repositories, repo)
+		}
+		if arch := binary.SelectAttrValue("arch", ""); arch != "" && !slices.Contains(candidate.Arches, arch) {
+			candidate.Arches = append(candidate.Arches, arch)
+		}
+	}
+
+	result := &BinaryToSourceResult{
+		BinaryName: params.BinaryName,
+		Candidates: []SourceCandidate{},
+	}
+	for _, candidate := range candidates {
+		sort.Strings(candidate.Repositories)
+		sort.Strings(candidate.Arches)
+		result.Candidates = append(result.Candidates, *candidate)
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		if result.Candidates[i].ProjectName != result.Candidates[j].ProjectName {
+			return result.Candidates[i].ProjectName < result.Candidates[j].ProjectName
+		}
+		return result.Candidates[i].PackageName < result.Candidates[j].PackageName
+	})
+	return nil, result, nil
+}
@@ -0,0 +1,37 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryToSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search/published/binary/id", r.URL.Path)
+		assert.Equal(t, "@name='libfoo1' and (@project='openSUSE:Factory' or @project='devel:foo')", r.URL.Query().Get("match"))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<collection matches="4">
+  <binary name="libfoo1" project="openSUSE:Factory" package="foo" repository="standard" version="1.2" release="1.1" arch="x86_64" filename="libfoo1-1.2-1.1.x86_64.rpm"/>
+  <binary name="libfoo1" project="openSUSE:Factory" package="foo" repository="standard" version="1.2" release="1.1" arch="aarch64" filename="libfoo1-1.2-1.1.aarch64.rpm"/>
+  <binary name="libfoo1" project="devel:foo" package="foo" repository="openSUSE_Tumbleweed" version="1.3" release="2.1" arch="x86_64" filename="libfoo1-1.3-2.1.x86_64.rpm"/>
+  <binary name="libfoo1" project="devel:foo" package="foo" repository="15.6" version="1.3" release="2.1" arch="x86_64" filename="libfoo1-1.3-2.1.x86_64.rpm"/>
+</collection>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.BinaryToSource(context.Background(), nil, BinaryToSourceParam{
+		BinaryName: "libfoo1",
+		Projects:   []string{"openSUSE:Factory", "devel:foo"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []SourceCandidate{
+		{ProjectName: "devel:foo", PackageName: "foo", Version: "1.3", Repositories: []string{"15.6", "openSUSE_Tumbleweed"}, Arches: []string{"x86_64"}},
+		{ProjectName: "openSUSE:Factory", PackageName: "foo", Version: "1.2", Repositories: []string{"standard"}, Arches: []string{"aarch64", "x86_64"}},
+	}, result.Candidates)
+}
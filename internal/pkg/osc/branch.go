@@ -61,10 +61,9 @@ func (cred OSCCredentials) BranchBundle(ctx context.Context, req *mcp.CallToolRe
 	}
 
 	httpReq.Header.Set("User-Agent", "osc-mcp")
-	httpReq.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(httpReq)
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := cred.do(httpReq)
 	if err != nil {
 		return nil, BranchResult{}, fmt.Errorf("failed to execute request: %w", err)
 	}
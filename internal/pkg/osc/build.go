@@ -5,12 +5,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -19,38 +25,314 @@ import (
 )
 
 type BuildParam struct {
-	ProjectName       string `json:"project_name" jsonschema:"Name of the project"`
-	BundleName        string `json:"bundle_name" jsonschema:"Name of the source package or bundle."`
-	VmType            string `json:"vm_type,omitempty" jsonschema:"VM type to use for build (e.g., chroot, kvm, podman, docker)"`
-	MultibuildPackage string `json:"multibuild_package,omitempty" jsonschema:"Specify the flavor of a multibuild package"`
-	Distribution      string `json:"distribution,omitempty" jsonschema:"Distribution to build against (e.g., openSUSE_Tumbleweed)."`
-	Arch              string `json:"arch,omitempty" jsonschema:"Architecture to build for (e.g., x86_64)."`
-	NrLines           int    `json:"nr_lines,omitempty" jsonschema:"Maximum number of lines to return in the log"`
+	ProjectName       string   `json:"project_name" jsonschema:"Name of the project"`
+	BundleName        string   `json:"bundle_name" jsonschema:"Name of the source package or bundle."`
+	VmType            string   `json:"vm_type,omitempty" jsonschema:"VM type to use for build (e.g., chroot, kvm, podman, docker)"`
+	MultibuildPackage string   `json:"multibuild_package,omitempty" jsonschema:"Specify the flavor of a multibuild package"`
+	Distribution      string   `json:"distribution,omitempty" jsonschema:"Distribution to build against (e.g., openSUSE_Tumbleweed)."`
+	Arch              string   `json:"arch,omitempty" jsonschema:"Architecture to build for (e.g., x86_64)."`
+	NrLines           int      `json:"nr_lines,omitempty" jsonschema:"Maximum number of lines to return in the log"`
+	SkipPreflight     bool     `json:"skip_preflight,omitempty" jsonschema:"Don't check the remote build status for unresolvable dependencies before building. Set this for local only packages or if the dependencies were already fixed locally."`
+	TimeoutSeconds    int      `json:"timeout_seconds,omitempty" jsonschema:"Abort the build after this many seconds and return the log up to this point. No timeout if not set."`
+	ExtraRepos        []string `json:"extra_repos,omitempty" jsonschema:"Local directories or file:// URLs with additional rpms which are preferred over the ones of the project repositories. Only affects the local build, the project meta isn't changed."`
+	NoVerify          bool     `json:"no_verify,omitempty" jsonschema:"Don't verify the signatures of the packages used for the local build."`
+	Jobs              int      `json:"jobs,omitempty" jsonschema:"Number of parallel jobs of the build. osc uses the number of CPUs if not set."`
+	Ccache            bool     `json:"ccache,omitempty" jsonschema:"Use ccache to speed up repeated builds of C/C++ packages."`
 }
 
 type BuildResult struct {
-	Error         string         `json:"error,omitempty"`
-	Success       bool           `json:"success"`
-	PackagesBuilt []string       `json:"packages_built,omitempty"`
-	RpmLint       map[string]any `json:"lint_report,omitempty"`
-	ParsedLog     any            `json:"parsed_log,omitempty"`
-	Buildroot     string         `json:"build-root,omitempty" jsonschema:"The root directory for the build"`
+	Error         string                 `json:"error,omitempty"`
+	Success       bool                   `json:"success"`
+	PackagesBuilt []rpm_pack             `json:"packages_built,omitempty"`
+	RpmLint       []buildlog.LintFinding `json:"lint_report,omitempty"`
+	ParsedLog     any                    `json:"parsed_log,omitempty"`
+	Buildroot     string                 `json:"build-root,omitempty" jsonschema:"The root directory for the build"`
+	MissingDeps   []string               `json:"missing_dependencies,omitempty"`
+	LogFile       string                 `json:"log_file,omitempty" jsonschema:"File with the raw build log, the parsed log is stored next to it with the suffix .json"`
+}
+
+const buildLogName = ".build.log"
+
+// checkExtraRepos returns the directories of the extra repositories of a
+// build. osc can only use local directories for them, so only plain paths and
+// file:// URLs of existing directories are accepted.
+func checkExtraRepos(repos []string) ([]string, error) {
+	dirs := []string{}
+	for _, repo := range repos {
+		dir := repo
+		if strings.Contains(repo, "://") {
+			u, err := url.Parse(repo)
+			if err != nil {
+				return nil, fmt.Errorf("invalid repository url %s: %w", repo, err)
+			}
+			if u.Scheme != "file" {
+				return nil, fmt.Errorf("unsupported repository url %s, only local directories and file:// urls can be used", repo)
+			}
+			dir = u.Path
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s doesn't exist: %w", repo, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("repository %s isn't a directory", repo)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// buildArgs returns the arguments of osc build and the build root if it is
+// in the work dir.
+func (cred *OSCCredentials) buildArgs(params BuildParam, dist, arch string, extraRepos []string) (args []string, buildRoot string) {
+	args = []string{"build", "--clean", "--trust-all-projects", "--noservice"}
+	for _, dir := range extraRepos {
+		args = append(args, "--prefer-pkgs", dir)
+	}
+	if params.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if params.Jobs > 0 {
+		args = append(args, "--jobs", strconv.Itoa(params.Jobs))
+	}
+	if params.Ccache {
+		args = append(args, "--ccache")
+	}
+	if params.VmType != "" && params.VmType != "chroot" {
+		args = append(args, "--vm-type", params.VmType, dist, arch)
+	} else {
+		if cred.buildRootInWorkdir {
+			buildRoot = fmt.Sprintf("%s/build-root/%s-%s", cred.TempDir, dist, arch)
+			args = append(args, "--root", buildRoot)
+		}
+	}
+	if params.MultibuildPackage != "" {
+		args = append(args, "-M", params.MultibuildPackage)
+	}
+	return args, buildRoot
+}
+
+// runCommand starts cmd in its own process group and calls onLine for every
+// line of its combined output. cmd must be created with exec.CommandContext
+// and ctx. If ctx is done, the whole process group is killed and the output
+// pipe is closed, so that children which inherited the pipe can't block the
+// reading of the output.
+func runCommand(ctx context.Context, cmd *exec.Cmd, onLine func(string)) (string, error) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	scanDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			stdout.Close()
+		case <-scanDone:
+		}
+	}()
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		out.WriteString(line)
+		out.WriteString("\n")
+		onLine(line)
+	}
+	close(scanDone)
+	err = cmd.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	return out.String(), err
+}
+
+var wroteRpmRegexp = regexp.MustCompile(`Wrote: (\S+\.rpm)\s*$`)
+
+// builtPackages returns the rpms written by a build. They are read from the
+// "Wrote:" lines of the build log, or from the RPMS and SRPMS directories of
+// buildRoot if it isn't empty.
+func builtPackages(rawLog string, buildRoot string) []rpm_pack {
+	packages := []rpm_pack{}
+	var files []string
+	if buildRoot != "" {
+		for _, dir := range []string{"RPMS/*", "SRPMS"} {
+			matches, _ := filepath.Glob(filepath.Join(buildRoot, "home/abuild/rpmbuild", dir, "*.rpm"))
+			files = append(files, matches...)
+		}
+	}
+	if len(files) == 0 {
+		for _, line := range strings.Split(rawLog, "\n") {
+			if m := wroteRpmRegexp.FindStringSubmatch(line); m != nil {
+				files = append(files, m[1])
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if pack := parseRPMFileName(name); pack.Name != "" {
+			packages = append(packages, pack)
+		}
+	}
+	return packages
+}
+
+// maxBuildLogs is the number of build logs kept per package
+const maxBuildLogs = 5
+
+// writeBuildLog stores the raw and the parsed build log in dir. The logs of
+// the previous builds are renamed with the time of the build as suffix and
+// only the last maxBuildLogs logs are kept.
+func writeBuildLog(dir string, rawLog string, log *buildlog.BuildLog) (string, error) {
+	logFile := filepath.Join(dir, buildLogName)
+	jsonFile := logFile + ".json"
+	if info, err := os.Stat(logFile); err == nil {
+		stamp := info.ModTime().Format("20060102-150405.000000000")
+		suffix := stamp
+		// the file system may only store seconds, never overwrite an older log
+		for i := 1; ; i++ {
+			if _, err := os.Lstat(fmt.Sprintf("%s.%s", logFile, suffix)); errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			suffix = fmt.Sprintf("%s-%d", stamp, i)
+		}
+		if err := os.Rename(logFile, fmt.Sprintf("%s.%s", logFile, suffix)); err != nil {
+			return "", fmt.Errorf("failed to rotate build log: %w", err)
+		}
+		if _, err := os.Stat(jsonFile); err == nil {
+			os.Rename(jsonFile, fmt.Sprintf("%s.%s.json", logFile, suffix))
+		}
+	}
+	old, _ := filepath.Glob(logFile + ".*[0-9]")
+	// the timestamps sort lexically, so the oldest logs come first
+	for len(old) >= maxBuildLogs {
+		os.Remove(old[0])
+		os.Remove(old[0] + ".json")
+		old = old[1:]
+	}
+
+	if err := os.WriteFile(logFile, []byte(rawLog), 0644); err != nil {
+		return "", fmt.Errorf("failed to write build log: %w", err)
+	}
+	allLines := strings.Count(rawLog, "\n") + 1
+	parsed, err := json.MarshalIndent(log.FormatJson(allLines, 0, true, "", ""), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal build log: %w", err)
+	}
+	if err := os.WriteFile(jsonFile, parsed, 0644); err != nil {
+		return "", fmt.Errorf("failed to write parsed build log: %w", err)
+	}
+	return logFile, nil
+}
+
+// localBuildLog returns the log of the last local build of the package for
+// dist and arch. If the log isn't in memory, e.g. after a restart, it is read
+// from the workdir.
+func (cred *OSCCredentials) localBuildLog(projectName, packageName, dist, arch string) (*buildlog.BuildLog, error) {
+	buildKey := fmt.Sprintf("%s/%s:%s:%s", projectName, packageName, arch, dist)
+	if log, ok := cred.BuildLogs[buildKey]; ok {
+		return log, nil
+	}
+	rawLog, err := os.ReadFile(filepath.Join(cred.TempDir, projectName, packageName, buildLogName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no local build of %s/%s found", projectName, packageName)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read build log: %w", err)
+	}
+	log := buildlog.Parse(string(rawLog))
+	if log.Distro != "" && (log.Distro != dist || log.Arch != arch) {
+		return nil, fmt.Errorf("the last local build of %s/%s was for %s %s", projectName, packageName, log.Distro, log.Arch)
+	}
+	if cred.BuildLogs == nil {
+		cred.BuildLogs = make(map[string]*buildlog.BuildLog)
+	}
+	cred.BuildLogs[buildKey] = log
+	return log, nil
 }
 
 type RunServicesParam struct {
 	ProjectName string   `json:"project_name" jsonschema:"Name of the project"`
 	BundleName  string   `json:"bundle_name" jsonschema:"Name of the source package or bundle."`
 	Services    []string `json:"services" jsonschema:"List of services to run. Useful services are: download_files: downloads the source files reference via an URI in the spec file with the pattern https://github.com/foo/baar/v%{version}.tar.gz#./%{name}-%{version}.tar.gz, go_modules: which creates a vendor directory for go files if the source has the same name as the project."`
+	Mode        string   `json:"mode,omitempty" jsonschema:"Subcommand of osc service used to run the services: runall (default) runs them regardless of their mode, run only runs the services with the default mode, manual, disabled and local only run the services with this mode."`
+}
+
+// serviceModes maps the modes of RunServicesParam to the osc service
+// subcommands.
+var serviceModes = map[string]string{
+	"":            "runall",
+	"runall":      "runall",
+	"run":         "run",
+	"manual":      "manualrun",
+	"manualrun":   "manualrun",
+	"disabled":    "disabledrun",
+	"disabledrun": "disabledrun",
+	"local":       "localrun",
+	"localrun":    "localrun",
+}
+
+func serviceSubcommand(mode string) (string, error) {
+	subcommand, ok := serviceModes[mode]
+	if !ok {
+		return "", fmt.Errorf("unknown service mode '%s', valid modes are: runall, run, manual, disabled, local", mode)
+	}
+	return subcommand, nil
+}
+
+type ServiceResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status" jsonschema:"succeeded, failed or skipped if a previous service failed"`
+	Message string `json:"message,omitempty" jsonschema:"The error messages of a failed service"`
 }
 
 type RunServicesResult struct {
-	Error   string `json:"error,omitempty"`
-	Success bool   `json:"success"`
-	Log     string `json:"log,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	Success        bool            `json:"success"`
+	ServiceResults []ServiceResult `json:"service_results,omitempty"`
+	Log            string          `json:"log,omitempty"`
+}
+
+var serviceErrorRegexp = regexp.MustCompile(`(?i)error|aborting|failed|not found`)
+
+// serviceResult returns the outcome of a service from its output and the
+// error of the osc call. The lines with error messages are collected in the
+// message of a failed service.
+func serviceResult(name, out string, runErr error) ServiceResult {
+	result := ServiceResult{Name: name, Status: "succeeded"}
+	if runErr == nil {
+		return result
+	}
+	result.Status = "failed"
+	messages := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && serviceErrorRegexp.MatchString(line) {
+			messages = append(messages, line)
+		}
+	}
+	if len(messages) == 0 {
+		messages = append(messages, runErr.Error())
+	}
+	result.Message = strings.Join(messages, "\n")
+	return result
 }
 
 func (cred *OSCCredentials) RunServices(ctx context.Context, req *mcp.CallToolRequest, params RunServicesParam) (*mcp.CallToolResult, any, error) {
 	slog.Debug("mcp tool call: RunServices", "session", req.Session.ID(), "params", params)
+	ctx, done := cred.startOperation(ctx, req, "run_services")
+	defer done()
 	if params.ProjectName == "" {
 		return nil, RunServicesResult{Success: false}, fmt.Errorf("project name must be specified")
 	}
@@ -60,6 +342,10 @@ func (cred *OSCCredentials) RunServices(ctx context.Context, req *mcp.CallToolRe
 	if len(params.Services) == 0 {
 		return nil, RunServicesResult{Success: false}, fmt.Errorf("at least one service must be specified")
 	}
+	subcommand, err := serviceSubcommand(params.Mode)
+	if err != nil {
+		return nil, RunServicesResult{Success: false}, err
+	}
 
 	cmdlineCfg := []string{"osc"}
 	configFile, err := cred.writeTempOscConfig()
@@ -73,28 +359,19 @@ func (cred *OSCCredentials) RunServices(ctx context.Context, req *mcp.CallToolRe
 	cmdDir := filepath.Join(cred.TempDir, params.ProjectName, params.BundleName)
 	progressToken := req.Params.GetProgressToken()
 
+	result := RunServicesResult{Success: true, ServiceResults: []ServiceResult{}}
 	var outAll bytes.Buffer
 	for _, service := range params.Services {
-		cmdline := append(cmdlineCfg, "service", "runall", service)
+		if !result.Success {
+			result.ServiceResults = append(result.ServiceResults, ServiceResult{Name: service, Status: "skipped"})
+			continue
+		}
+		cmdline := append(cmdlineCfg, "service", subcommand, service)
 		oscCmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
 		oscCmd.Dir = cmdDir
 
-		stdout, err := oscCmd.StdoutPipe()
-		if err != nil {
-			return nil, RunServicesResult{Error: "failed to get stdout pipe: " + err.Error(), Success: false}, nil
-		}
-		oscCmd.Stderr = oscCmd.Stdout
 		slog.Info("starting osc service run", slog.String("command", oscCmd.String()), slog.String("dir", cmdDir))
-		if err := oscCmd.Start(); err != nil {
-			slog.Error("failed to start osc service run", "error", err)
-			return nil, RunServicesResult{Error: "failed to start service run: " + err.Error(), Success: false}, nil
-		}
-		var out bytes.Buffer
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			line := scanner.Text()
-			out.WriteString(line)
-			out.WriteString("\n")
+		out, err := runCommand(ctx, oscCmd, func(line string) {
 			if progressToken != nil {
 				err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
 					ProgressToken: progressToken,
@@ -104,25 +381,24 @@ func (cred *OSCCredentials) RunServices(ctx context.Context, req *mcp.CallToolRe
 					slog.Warn("failed to send progress notification", "error", err)
 				}
 			}
+		})
+		if oscCmd.Process == nil {
+			slog.Error("failed to start osc service run", "error", err)
+			return nil, RunServicesResult{Error: "failed to start service run: " + err.Error(), Success: false}, nil
 		}
-		outAll.Write(out.Bytes())
-
-		err = oscCmd.Wait()
+		outAll.WriteString(out)
+		result.ServiceResults = append(result.ServiceResults, serviceResult(service, out, err))
 		if err != nil {
 			slog.Error("failed to run service", slog.String("command", oscCmd.String()), "error", err)
-			return nil, RunServicesResult{
-				Error:   err.Error(),
-				Success: false,
-				Log:     outAll.String(),
-			}, nil
+			result.Error = err.Error()
+			result.Success = false
+			continue
 		}
 		slog.Debug("osc service finished successfully", slog.String("command", oscCmd.String()))
 	}
 
-	return nil, RunServicesResult{
-		Success: true,
-		Log:     outAll.String(),
-	}, nil
+	result.Log = outAll.String()
+	return nil, result, nil
 }
 
 func BuildInputSchema() *jsonschema.Schema {
@@ -134,12 +410,18 @@ func BuildInputSchema() *jsonschema.Schema {
 func (cred *OSCCredentials) Build(ctx context.Context, req *mcp.CallToolRequest, params BuildParam) (*mcp.CallToolResult, any, error) {
 	result := BuildResult{}
 	slog.Debug("mcp tool call: Build", "session", req.Session.ID(), "params", params)
+	ctx, done := cred.startOperation(ctx, req, "run_build")
+	defer done()
 	if params.ProjectName == "" {
 		return nil, result, fmt.Errorf("project name must be specified")
 	}
 	if params.BundleName == "" {
 		return nil, result, fmt.Errorf("package or bundle name must be specified")
 	}
+	extraRepos, err := checkExtraRepos(params.ExtraRepos)
+	if err != nil {
+		return nil, result, err
+	}
 
 	cmdline := []string{"osc"}
 	configFile, err := cred.writeTempOscConfig()
@@ -195,40 +477,45 @@ func (cred *OSCCredentials) Build(ctx context.Context, req *mcp.CallToolRequest,
 		}
 	}
 
-	cmdline = append(cmdline, "build", "--clean", "--trust-all-projects", "--noservice")
-	if params.VmType != "" && params.VmType != "chroot" {
-		cmdline = append(cmdline, "--vm-type", params.VmType, dist, arch)
-	} else {
-		if cred.buildRootInWorkdir {
-			buildRoot := fmt.Sprintf("%s/build-root/%s-%s", cred.TempDir, dist, arch)
-			cmdline = append(cmdline, "--root", buildRoot)
-			result.Buildroot = buildRoot
+	if !params.SkipPreflight {
+		packageName := params.BundleName
+		if params.MultibuildPackage != "" {
+			packageName = fmt.Sprintf("%s:%s", params.BundleName, params.MultibuildPackage)
+		}
+		status, err := cred.GetBuildStatus(ctx, params.ProjectName, dist, arch, packageName)
+		if err != nil {
+			slog.Debug("could not get remote build status, skipping pre-flight check", "error", err)
+		} else if status.Code == "unresolvable" {
+			missingDeps, err := cred.getMissingDeps(ctx, params.ProjectName, dist, arch, packageName)
+			if err != nil {
+				slog.Warn("failed to get missing dependencies", "error", err)
+			}
+			result.MissingDeps = missingDeps
+			if len(missingDeps) > 0 {
+				result.Error = fmt.Sprintf("package is unresolvable on the server. Missing dependencies: %s", strings.Join(missingDeps, ", "))
+			} else {
+				result.Error = fmt.Sprintf("package is unresolvable on the server: %s", status.Details)
+			}
+			return nil, result, nil
 		}
-	}
-	if params.MultibuildPackage != "" {
-		cmdline = append(cmdline, "-M", params.MultibuildPackage)
 	}
 
-	oscCmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
-	oscCmd.Dir = cmdDir
+	args, buildRoot := cred.buildArgs(params, dist, arch, extraRepos)
+	cmdline = append(cmdline, args...)
+	result.Buildroot = buildRoot
 
-	stdout, err := oscCmd.StdoutPipe()
-	if err != nil {
-		return nil, nil, err
+	buildCtx := ctx
+	if params.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		buildCtx, cancel = context.WithTimeout(ctx, time.Duration(params.TimeoutSeconds)*time.Second)
+		defer cancel()
 	}
-	oscCmd.Stderr = oscCmd.Stdout
+	oscCmd := exec.CommandContext(buildCtx, cmdline[0], cmdline[1:]...)
+	oscCmd.Dir = cmdDir
+
 	buildStartTime := time.Now()
 	slog.Info("starting osc build", slog.String("command", oscCmd.String()), slog.String("dir", cmdDir))
-	if err := oscCmd.Start(); err != nil {
-		slog.Error("failed to start osc build", "error", err)
-		return nil, nil, err
-	}
-	var out bytes.Buffer
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		line := scanner.Text()
-		out.WriteString(line)
-		out.WriteString("\n")
+	rawLog, buildErr := runCommand(buildCtx, oscCmd, func(line string) {
 		if progressToken != nil {
 			err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
 				ProgressToken: progressToken,
@@ -238,12 +525,14 @@ func (cred *OSCCredentials) Build(ctx context.Context, req *mcp.CallToolRequest,
 				slog.Warn("failed to send progress notification", "error", err)
 			}
 		}
+	})
+	if oscCmd.Process == nil {
+		slog.Error("failed to start osc build", "error", buildErr)
+		return nil, nil, buildErr
 	}
-
-	buildErr := oscCmd.Wait()
 	buildDuration := time.Since(buildStartTime)
 
-	buildLog := buildlog.Parse(out.String())
+	buildLog := buildlog.Parse(rawLog)
 
 	buildKey := fmt.Sprintf("%s/%s:%s:%s", params.ProjectName, params.BundleName, arch, dist)
 	if cred.BuildLogs == nil {
@@ -251,15 +540,25 @@ func (cred *OSCCredentials) Build(ctx context.Context, req *mcp.CallToolRequest,
 	}
 	cred.BuildLogs[buildKey] = buildLog
 	cred.LastBuildKey = buildKey
+	if logFile, err := writeBuildLog(cmdDir, rawLog, buildLog); err != nil {
+		slog.Warn("failed to store build log", "error", err)
+	} else {
+		result.LogFile = logFile
+	}
 
 	nrLines := params.NrLines
 	if nrLines <= 0 {
 		nrLines = 1000
 	}
 
+	// rpmlint errors are a common reason for a failed build
+	result.RpmLint = buildLog.RpmLint()
 	if buildErr != nil {
 		slog.Error("failed to run build", slog.String("command", oscCmd.String()), "error", buildErr, "duration", buildDuration)
 		result.Error = buildErr.Error()
+		if errors.Is(buildErr, context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("build timed out after %d seconds", params.TimeoutSeconds)
+		}
 		result.ParsedLog = buildLog.FormatJson(nrLines, 0, false, "", "")
 		result.Success = false
 		return nil, result, nil
@@ -267,8 +566,7 @@ func (cred *OSCCredentials) Build(ctx context.Context, req *mcp.CallToolRequest,
 
 	slog.Info("osc build finished successfully", "project", params.ProjectName, "package", params.BundleName, "duration", buildDuration)
 	result.Success = true
-	result.PackagesBuilt = []string{}
-	result.RpmLint = map[string]any{}
+	result.PackagesBuilt = builtPackages(rawLog, result.Buildroot)
 	result.ParsedLog = buildLog.FormatJson(nrLines, 0, false, "", "")
 	return nil, result, nil
 }
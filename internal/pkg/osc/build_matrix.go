@@ -0,0 +1,169 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetPackageBuildMatrixParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+}
+
+type PackageBuildMatrix struct {
+	ProjectName string            `json:"project_name"`
+	PackageName string            `json:"package_name"`
+	Status      map[string]string `json:"status" jsonschema:"Build status code for every repository/arch, with the flavor appended for multibuild packages"`
+}
+
+// getPackageBuildMatrix reads the build status of a package for all
+// repositories and architectures.
+func (cred *OSCCredentials) getPackageBuildMatrix(ctx context.Context, projectName, packageName string) (*PackageBuildMatrix, error) {
+	query := url.Values{}
+	query.Set("package", packageName)
+	query.Set("view", "status")
+	query.Set("multibuild", "1")
+	query.Set("locallink", "1")
+	resp, err := cred.apiGetRequest(ctx, fmt.Sprintf("build/%s/_result?%s", projectName, query.Encode()), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resultList := doc.SelectElement("resultlist")
+	if resultList == nil {
+		return nil, fmt.Errorf("no resultlist found in build result of %s/%s", projectName, packageName)
+	}
+
+	matrix := &PackageBuildMatrix{
+		ProjectName: projectName,
+		PackageName: packageName,
+		Status:      map[string]string{},
+	}
+	if status, ok := parseBuildStatus(resultList)[packageName]; ok {
+		matrix.Status = status
+	}
+	return matrix, nil
+}
+
+func (cred *OSCCredentials) GetPackageBuildMatrix(ctx context.Context, req *mcp.CallToolRequest, params GetPackageBuildMatrixParam) (*mcp.CallToolResult, *PackageBuildMatrix, error) {
+	slog.Debug("mcp tool call: GetPackageBuildMatrix", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	matrix, err := cred.getPackageBuildMatrix(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, matrix, nil
+}
+
+const defaultBuildCleanTimeout = 1800
+
+// buildStatusPollInterval is the time between two polls of the build results
+// while waiting for builds to finish.
+var buildStatusPollInterval = 30 * time.Second
+
+// buildCodeClean are the build codes which don't block a submission
+var buildCodeClean = []string{"succeeded", "disabled", "excluded"}
+
+// buildCodeFailed are the build codes which need a fix
+var buildCodeFailed = []string{"failed", "unresolvable", "broken"}
+
+type IsBuildCleanParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Wait        bool   `json:"wait,omitempty" jsonschema:"Wait until no build is pending anymore."`
+	Timeout     int    `json:"timeout,omitempty" jsonschema:"Maximal time in seconds to wait for pending builds. Defaults to 1800."`
+}
+
+type IsBuildCleanResult struct {
+	ProjectName string            `json:"project_name"`
+	PackageName string            `json:"package_name"`
+	Clean       bool              `json:"clean" jsonschema:"True if the bundle succeeded, is disabled or excluded everywhere"`
+	Failed      map[string]string `json:"failed,omitempty" jsonschema:"Repository/arch which failed, are unresolvable or broken"`
+	Pending     map[string]string `json:"pending,omitempty" jsonschema:"Repository/arch which are still building or scheduled"`
+	Note        string            `json:"note,omitempty"`
+}
+
+// checkBuildClean sorts the status of a build matrix into failed and pending
+// builds.
+func checkBuildClean(matrix *PackageBuildMatrix) *IsBuildCleanResult {
+	result := &IsBuildCleanResult{
+		ProjectName: matrix.ProjectName,
+		PackageName: matrix.PackageName,
+		Failed:      map[string]string{},
+		Pending:     map[string]string{},
+	}
+	for repoArch, code := range matrix.Status {
+		switch {
+		case slices.Contains(buildCodeClean, code):
+		case slices.Contains(buildCodeFailed, code):
+			result.Failed[repoArch] = code
+		default:
+			result.Pending[repoArch] = code
+		}
+	}
+	result.Clean = len(result.Failed) == 0 && len(result.Pending) == 0
+	if len(matrix.Status) == 0 {
+		result.Clean = false
+		result.Note = "There are no build results for the bundle."
+	}
+	return result
+}
+
+func (cred *OSCCredentials) IsBuildClean(ctx context.Context, req *mcp.CallToolRequest, params IsBuildCleanParam) (*mcp.CallToolResult, *IsBuildCleanResult, error) {
+	slog.Debug("mcp tool call: IsBuildClean", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = defaultBuildCleanTimeout
+	}
+	ctx, done := cred.startOperation(ctx, req, "is_build_clean")
+	defer done()
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		matrix, err := cred.getPackageBuildMatrix(ctx, params.ProjectName, params.PackageName)
+		if err != nil {
+			return nil, nil, err
+		}
+		result := checkBuildClean(matrix)
+		if !params.Wait || len(result.Pending) == 0 {
+			return nil, result, nil
+		}
+		if time.Now().After(deadline) {
+			result.Note = fmt.Sprintf("builds are still pending after %d seconds", timeout)
+			return nil, result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(buildStatusPollInterval):
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPackageBuildMatrix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/build/home:testuser/_result", r.URL.Path)
+		assert.Equal(t, "foo", r.URL.Query().Get("package"))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<resultlist state="abc">
+  <result project="home:testuser" repository="openSUSE_Tumbleweed" arch="x86_64" code="published" state="published">
+    <status package="foo" code="succeeded"/>
+    <status package="foo:docs" code="failed"/>
+  </result>
+  <result project="home:testuser" repository="openSUSE_Tumbleweed" arch="aarch64" code="building" state="building">
+    <status package="foo" code="building"/>
+    <status package="foo:docs" code="excluded"/>
+  </result>
+</resultlist>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.GetPackageBuildMatrix(context.Background(), nil, GetPackageBuildMatrixParam{ProjectName: "home:testuser", PackageName: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"openSUSE_Tumbleweed/x86_64":       "succeeded",
+		"openSUSE_Tumbleweed/x86_64/docs":  "failed",
+		"openSUSE_Tumbleweed/aarch64":      "building",
+		"openSUSE_Tumbleweed/aarch64/docs": "excluded",
+	}, result.Status)
+}
+
+func TestIsBuildClean(t *testing.T) {
+	oldInterval := buildStatusPollInterval
+	buildStatusPollInterval = time.Millisecond
+	defer func() { buildStatusPollInterval = oldInterval }()
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		code := "building"
+		if polls > 1 {
+			code = "succeeded"
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `<resultlist>
+  <result repository="openSUSE_Tumbleweed" arch="x86_64"><status package="foo" code="%s"/></result>
+  <result repository="openSUSE_Tumbleweed" arch="i586"><status package="foo" code="excluded"/></result>
+  <result repository="15.6" arch="x86_64"><status package="foo" code="succeeded"/></result>
+</resultlist>`, code)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.IsBuildClean(context.Background(), nil, IsBuildCleanParam{ProjectName: "home:testuser", PackageName: "foo"})
+	assert.NoError(t, err)
+	assert.False(t, result.Clean)
+	assert.Equal(t, map[string]string{"openSUSE_Tumbleweed/x86_64": "building"}, result.Pending)
+	assert.Empty(t, result.Failed)
+
+	polls = 0
+	_, result, err = cred.IsBuildClean(context.Background(), nil, IsBuildCleanParam{ProjectName: "home:testuser", PackageName: "foo", Wait: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, polls)
+	assert.True(t, result.Clean)
+
+	result = checkBuildClean(&PackageBuildMatrix{Status: map[string]string{"a/x86_64": "failed", "b/x86_64": "unresolvable", "c/x86_64": "scheduled"}})
+	assert.False(t, result.Clean)
+	assert.Equal(t, map[string]string{"a/x86_64": "failed", "b/x86_64": "unresolvable"}, result.Failed)
+	assert.Equal(t, map[string]string{"c/x86_64": "scheduled"}, result.Pending)
+}
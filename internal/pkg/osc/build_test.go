@@ -0,0 +1,222 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/openSUSE/osc-mcp/internal/pkg/buildlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteBuildLog(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxBuildLogs+2; i++ {
+		rawLog := "[    1s] build " + string(rune('a'+i)) + "\n"
+		logFile, err := writeBuildLog(dir, rawLog, buildlog.Parse(rawLog))
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, buildLogName), logFile)
+		content, err := os.ReadFile(logFile)
+		assert.NoError(t, err)
+		assert.Equal(t, rawLog, string(content))
+		// give every build a distinct time stamp
+		past := time.Now().Add(time.Duration(i-maxBuildLogs-2) * time.Hour)
+		assert.NoError(t, os.Chtimes(logFile, past, past))
+	}
+	_, err := os.Stat(filepath.Join(dir, buildLogName+".json"))
+	assert.NoError(t, err)
+	rotated, _ := filepath.Glob(filepath.Join(dir, buildLogName+".*[0-9]"))
+	assert.Len(t, rotated, maxBuildLogs-1)
+	rotatedJson, _ := filepath.Glob(filepath.Join(dir, buildLogName+".*[0-9].json"))
+	assert.Len(t, rotatedJson, maxBuildLogs-1)
+
+	// builds which finish at the same time don't overwrite each other
+	dir = t.TempDir()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		rawLog := "[    1s] build " + string(rune('a'+i)) + "\n"
+		logFile, err := writeBuildLog(dir, rawLog, buildlog.Parse(rawLog))
+		assert.NoError(t, err)
+		assert.NoError(t, os.Chtimes(logFile, now, now))
+	}
+	rotated, _ = filepath.Glob(filepath.Join(dir, buildLogName+".*[0-9]"))
+	assert.Len(t, rotated, 2)
+	var contents []string
+	for _, file := range rotated {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	assert.ElementsMatch(t, []string{"[    1s] build a\n", "[    1s] build b\n"}, contents)
+}
+
+func TestLocalBuildLog(t *testing.T) {
+	rawLog := `[    0s] Using BUILD_ROOT=/var/tmp/build-root/openSUSE_Tumbleweed-x86_64
+[    1s] started "build hello.spec"
+[    5s] -----------------------------------------------------------------
+[    6s] make: *** [Makefile:12: all] Error 1
+[    7s] error: Bad exit status from /var/tmp/rpm-tmp.1234 (%build)
+`
+	cred := &OSCCredentials{TempDir: t.TempDir()}
+	dir := filepath.Join(cred.TempDir, "home:testuser", "hello")
+	assert.NoError(t, os.MkdirAll(dir, 0o755))
+	_, err := writeBuildLog(dir, rawLog, buildlog.Parse(rawLog))
+	assert.NoError(t, err)
+
+	// the log is read from the workdir after a restart
+	params := BuildLogParam{ProjectName: "home:testuser", PackageName: "hello", RepositoryName: "openSUSE_Tumbleweed", Local: true}
+	_, result, err := cred.BuildLog(context.Background(), &mcp.CallToolRequest{}, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", result["Properties"].(map[string]string)["Name"])
+	assert.Contains(t, cred.BuildLogs, "home:testuser/hello:x86_64:openSUSE_Tumbleweed")
+
+	params.ArchitectureName = "aarch64"
+	_, _, err = cred.BuildLog(context.Background(), &mcp.CallToolRequest{}, params)
+	assert.ErrorContains(t, err, "was for openSUSE_Tumbleweed x86_64")
+
+	params.PackageName = "missing"
+	_, _, err = cred.BuildLog(context.Background(), &mcp.CallToolRequest{}, params)
+	assert.ErrorContains(t, err, "no local build")
+}
+
+func TestBuiltPackages(t *testing.T) {
+	rawLog := `[  101s] Processing files: hello-2.12-1.1.x86_64
+[  102s] Wrote: /home/abuild/rpmbuild/SRPMS/hello-2.12-1.1.src.rpm
+[  102s] Wrote: /home/abuild/rpmbuild/RPMS/x86_64/hello-2.12-1.1.x86_64.rpm
+[  102s] Wrote: /home/abuild/rpmbuild/RPMS/noarch/hello-lang-2.12-1.1.noarch.rpm
+[  103s] Executing(%clean): /bin/sh -e /var/tmp/rpm-tmp.abc
+[  110s] build-host finished "build hello.spec" at Thu Oct 16 10:00:00 UTC 2026.
+`
+	expected := []rpm_pack{
+		{Name: "hello", Version: "2.12-1.1", Arch: "src"},
+		{Name: "hello", Version: "2.12-1.1", Arch: "x86_64"},
+		{Name: "hello-lang", Version: "2.12-1.1", Arch: "noarch"},
+	}
+	assert.Equal(t, expected, builtPackages(rawLog, ""))
+	assert.Equal(t, []rpm_pack{}, builtPackages("[    1s] nothing built\n", ""))
+
+	buildRoot := t.TempDir()
+	rpmDir := filepath.Join(buildRoot, "home/abuild/rpmbuild/RPMS/x86_64")
+	assert.NoError(t, os.MkdirAll(rpmDir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(rpmDir, "hello-2.12-1.1.x86_64.rpm"), nil, 0o644))
+	assert.Equal(t, []rpm_pack{{Name: "hello", Version: "2.12-1.1", Arch: "x86_64"}}, builtPackages(rawLog, buildRoot))
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	// the background sleep inherits the output pipe and has to be killed too
+	cmd := exec.CommandContext(ctx, "sh", "-c", "echo started; sleep 30 & sleep 30")
+	lines := []string{}
+	start := time.Now()
+	out, err := runCommand(ctx, cmd, func(line string) { lines = append(lines, line) })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, "started\n", out)
+	assert.Equal(t, []string{"started"}, lines)
+}
+
+func TestRunCommand(t *testing.T) {
+	cmd := exec.CommandContext(context.Background(), "sh", "-c", "echo out; echo err >&2; exit 3")
+	out, err := runCommand(context.Background(), cmd, func(string) {})
+	assert.Error(t, err)
+	assert.Equal(t, "out\nerr\n", out)
+}
+
+func TestBuildArgs(t *testing.T) {
+	cred := &OSCCredentials{TempDir: "/tmp/work", buildRootInWorkdir: true}
+	repoDir := t.TempDir()
+	repos, err := checkExtraRepos([]string{repoDir, "file://" + repoDir})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{repoDir, repoDir}, repos)
+
+	args, buildRoot := cred.buildArgs(BuildParam{NoVerify: true, MultibuildPackage: "flavor"}, "openSUSE_Tumbleweed", "x86_64", repos)
+	assert.Equal(t, []string{"build", "--clean", "--trust-all-projects", "--noservice",
+		"--prefer-pkgs", repoDir, "--prefer-pkgs", repoDir, "--no-verify",
+		"--root", "/tmp/work/build-root/openSUSE_Tumbleweed-x86_64", "-M", "flavor"}, args)
+	assert.Equal(t, "/tmp/work/build-root/openSUSE_Tumbleweed-x86_64", buildRoot)
+
+	args, _ = cred.buildArgs(BuildParam{Jobs: 8, Ccache: true}, "openSUSE_Tumbleweed", "x86_64", nil)
+	assert.Equal(t, []string{"build", "--clean", "--trust-all-projects", "--noservice",
+		"--jobs", "8", "--ccache", "--root", "/tmp/work/build-root/openSUSE_Tumbleweed-x86_64"}, args)
+
+	args, buildRoot = cred.buildArgs(BuildParam{VmType: "kvm"}, "openSUSE_Tumbleweed", "x86_64", nil)
+	assert.Equal(t, []string{"build", "--clean", "--trust-all-projects", "--noservice",
+		"--vm-type", "kvm", "openSUSE_Tumbleweed", "x86_64"}, args)
+	assert.Empty(t, buildRoot)
+
+	_, err = checkExtraRepos([]string{"https://download.opensuse.org/repositories/foo/"})
+	assert.ErrorContains(t, err, "unsupported repository url")
+	_, err = checkExtraRepos([]string{filepath.Join(repoDir, "missing")})
+	assert.ErrorContains(t, err, "doesn't exist")
+}
+
+func TestBuildFailedRpmLint(t *testing.T) {
+	// a fake osc which fails the build in the rpmlint check
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+cat <<EOF
+[   10s] RPMLINT report:
+[   10s] ===============
+[   11s] foo.x86_64: E: no-binary
+[   11s] The package should be of the noarch architecture.
+[   11s] foo.src:12: W: macro-in-comment %{buildroot}
+[   11s] 2 packages and 0 specfiles checked; 1 errors, 1 warnings.
+EOF
+exit 1
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "osc"), []byte(script), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cred := &OSCCredentials{TempDir: t.TempDir()}
+	assert.NoError(t, os.MkdirAll(filepath.Join(cred.TempDir, "home:testuser", "foo"), 0o755))
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, res, err := cred.Build(context.Background(), req, BuildParam{
+		ProjectName:   "home:testuser",
+		BundleName:    "foo",
+		Distribution:  "openSUSE_Tumbleweed",
+		Arch:          "x86_64",
+		SkipPreflight: true,
+	})
+	assert.NoError(t, err)
+	result := res.(BuildResult)
+	assert.False(t, result.Success)
+	assert.NotEmpty(t, result.Error)
+	assert.Equal(t, []buildlog.LintFinding{
+		{Package: "foo.x86_64", Severity: "E", Check: "no-binary"},
+		{Package: "foo.src", Line: "12", Severity: "W", Check: "macro-in-comment", Detail: "%{buildroot}"},
+	}, result.RpmLint)
+}
+
+func TestServiceSubcommand(t *testing.T) {
+	for mode, expected := range map[string]string{"": "runall", "run": "run", "manual": "manualrun", "disabled": "disabledrun", "localrun": "localrun"} {
+		subcommand, err := serviceSubcommand(mode)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, subcommand)
+	}
+	_, err := serviceSubcommand("remoterun")
+	assert.ErrorContains(t, err, "unknown service mode")
+}
+
+func TestServiceResult(t *testing.T) {
+	failedLog := `Preparing sources in /tmp/work/home:user/foo ...
+Running source_service: download_files ...
+2026-10-16 10:00:00 [INFO] Fetching https://example.com/foo-1.0.tar.gz
+ERROR: 404 Client Error: Not Found for url: https://example.com/foo-1.0-extra.tar.gz
+Aborting: service call failed: /usr/lib/obs/service/download_files --outdir /tmp/work/home:user/foo/tmpxyz
+`
+	assert.Equal(t, ServiceResult{Name: "download_files", Status: "succeeded"},
+		serviceResult("download_files", "Running source_service: download_files ...\n", nil))
+	assert.Equal(t, ServiceResult{
+		Name:    "download_files",
+		Status:  "failed",
+		Message: "ERROR: 404 Client Error: Not Found for url: https://example.com/foo-1.0-extra.tar.gz\nAborting: service call failed: /usr/lib/obs/service/download_files --outdir /tmp/work/home:user/foo/tmpxyz",
+	}, serviceResult("download_files", failedLog, errors.New("exit status 1")))
+	assert.Equal(t, ServiceResult{Name: "go_modules", Status: "failed", Message: "exit status 2"},
+		serviceResult("go_modules", "Running source_service: go_modules ...\n", errors.New("exit status 2")))
+}
@@ -0,0 +1,93 @@
+package osc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type BuildInfoParam struct {
+	ProjectName      string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName      string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	RepositoryName   string `json:"repository_name" jsonschema:"Repository name"`
+	ArchitectureName string `json:"architecture_name,omitempty" jsonschema:"Architecture name"`
+}
+
+type BuildInfoPath struct {
+	Project    string `xml:"project,attr" json:"project"`
+	Repository string `xml:"repository,attr" json:"repository"`
+}
+
+type BuildInfoDep struct {
+	Name       string `xml:"name,attr" json:"name"`
+	Version    string `xml:"version,attr" json:"version,omitempty"`
+	Release    string `xml:"release,attr" json:"release,omitempty"`
+	Arch       string `xml:"arch,attr" json:"arch,omitempty"`
+	Project    string `xml:"project,attr" json:"project,omitempty"`
+	Repository string `xml:"repository,attr" json:"repository,omitempty"`
+	Preinstall bool   `xml:"preinstall,attr" json:"preinstall,omitempty"`
+	VMInstall  bool   `xml:"vminstall,attr" json:"vminstall,omitempty"`
+	RunScripts bool   `xml:"runscripts,attr" json:"runscripts,omitempty"`
+	NotMeta    bool   `xml:"notmeta,attr" json:"notmeta,omitempty"`
+}
+
+type BuildInfo struct {
+	XMLName    xml.Name        `xml:"buildinfo" json:"-"`
+	Project    string          `xml:"project,attr" json:"project"`
+	Repository string          `xml:"repository,attr" json:"repository"`
+	Package    string          `xml:"package,attr" json:"package"`
+	Arch       string          `xml:"arch" json:"arch"`
+	Error      string          `xml:"error" json:"error,omitempty"`
+	Paths      []BuildInfoPath `xml:"path" json:"paths"`
+	Deps       []BuildInfoDep  `xml:"bdep" json:"build_dependencies"`
+}
+
+// getBuildInfo retrieves the build environment which OBS computed for a package.
+func (cred *OSCCredentials) getBuildInfo(ctx context.Context, projectName, repositoryName, architectureName, packageName string) (*BuildInfo, error) {
+	url := fmt.Sprintf("%s/build/%s/%s/%s/%s/_buildinfo", cred.GetAPiAddr(), projectName, repositoryName, architectureName, packageName)
+	slog.Debug("getBuildInfo", "url", url)
+	body, statusCode, err := cred.getFromApi(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get build info: status code %d, body: %s", statusCode, string(body))
+	}
+
+	var info BuildInfo
+	if err := xml.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse build info XML: %w", err)
+	}
+	if info.Paths == nil {
+		info.Paths = []BuildInfoPath{}
+	}
+	if info.Deps == nil {
+		info.Deps = []BuildInfoDep{}
+	}
+	return &info, nil
+}
+
+func (cred *OSCCredentials) GetBuildInfo(ctx context.Context, req *mcp.CallToolRequest, params BuildInfoParam) (*mcp.CallToolResult, *BuildInfo, error) {
+	slog.Debug("mcp tool call: GetBuildInfo", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name must be specified")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name must be specified")
+	}
+	if params.RepositoryName == "" {
+		return nil, nil, fmt.Errorf("repository name must be specified")
+	}
+	if params.ArchitectureName == "" {
+		params.ArchitectureName = defArch
+	}
+	info, err := cred.getBuildInfo(ctx, params.ProjectName, params.RepositoryName, params.ArchitectureName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, info, nil
+}
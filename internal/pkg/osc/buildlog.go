@@ -25,10 +25,9 @@ func (cred *OSCCredentials) getFromApi(ctx context.Context, url string) ([]byte,
 		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -49,10 +48,9 @@ func (cred *OSCCredentials) getFromApiWithProgress(ctx context.Context, url stri
 		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("User-Agent", "osc-mcp")
-	httpReq.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(httpReq)
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := cred.do(httpReq)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -212,6 +210,9 @@ type BuildLogPackage struct {
 	XMLName xml.Name `xml:"package"`
 	Name    string   `xml:"name,attr"`
 	Deps    []Dep    `xml:"dep"`
+	// PkgDeps are the packages the package depends on, or with the
+	// revpkgnames view the packages which depend on the package.
+	PkgDeps []string `xml:"pkgdep"`
 }
 
 type Dep struct {
@@ -222,7 +223,16 @@ type Dep struct {
 
 // GetBuildDepInfo retrieves the build dependency information for a project.
 func (cred *OSCCredentials) GetBuildDepInfo(ctx context.Context, projectName, repositoryName, architectureName string) (*BuildDepInfo, error) {
+	return cred.getBuildDepInfoView(ctx, projectName, repositoryName, architectureName, "")
+}
+
+// getBuildDepInfoView retrieves the build dependency information in the
+// given view, like revpkgnames, or the default view if view is empty.
+func (cred *OSCCredentials) getBuildDepInfoView(ctx context.Context, projectName, repositoryName, architectureName, view string) (*BuildDepInfo, error) {
 	url := fmt.Sprintf("%s/build/%s/%s/%s/_builddepinfo", cred.GetAPiAddr(), projectName, repositoryName, architectureName)
+	if view != "" {
+		url += "?view=" + view
+	}
 	slog.Debug("GetBuildDepInfo", "url", url)
 	body, statusCode, err := cred.getFromApi(ctx, url)
 	if err != nil {
@@ -239,6 +249,28 @@ func (cred *OSCCredentials) GetBuildDepInfo(ctx context.Context, projectName, re
 	return &depInfo, nil
 }
 
+// getMissingDeps returns the dependencies of a package which are marked as
+// missing in the build dependency info of the repository.
+func (cred *OSCCredentials) getMissingDeps(ctx context.Context, projectName, repositoryName, architectureName, packageName string) ([]string, error) {
+	depInfo, err := cred.GetBuildDepInfo(ctx, projectName, repositoryName, architectureName)
+	if err != nil {
+		return nil, err
+	}
+
+	var missingDeps []string
+	for _, p := range depInfo.Packages {
+		if p.Name == packageName {
+			for _, d := range p.Deps {
+				if d.State == "missing" {
+					missingDeps = append(missingDeps, d.Name)
+				}
+			}
+			break
+		}
+	}
+	return missingDeps, nil
+}
+
 // getBuildLogRaw retrieves the build log for a given package and returns the raw content.
 func (cred *OSCCredentials) getBuildLogRaw(ctx context.Context, projectName, repositoryName, architectureName, packageName string, req *mcp.CallToolRequest) (string, error) {
 	slog.Debug("GetBuildLogRaw", "project", projectName, "repository", repositoryName, "architecture", architectureName, "package", packageName)
@@ -277,6 +309,8 @@ func (cred *OSCCredentials) GetBuildLogRaw(ctx context.Context, projectName, rep
 }
 
 const defArch = "x86_64"
+
+// maxLines is the default for the maximal number of log lines returned at once
 const maxLines = 1000
 
 func BuildLogInputSchema() *jsonschema.Schema {
@@ -286,16 +320,50 @@ func BuildLogInputSchema() *jsonschema.Schema {
 }
 
 type BuildLogParam struct {
-	ProjectName      string `json:"project_name" jsonschema:"Name of the project"`
-	PackageName      string `json:"package_name" jsonschema:"Name of the package"`
-	Flavor           string `json:"flavor,omitempty" jsonschema:"Flavor of the package. In most cases leave this empty, build falvors only exist if there is a _multibuild file in the source."`
-	RepositoryName   string `json:"repository_name" jsonschema:"Repository name, use openSUSE_Tumblweed if the not requested otherwise"`
-	ArchitectureName string `json:"architecture_name,omitempty" jsonschema:"Architecture name"`
-	NrLines          int    `json:"nr_lines,omitempty" jsonschema:"Maximum number of lines"`
-	Offset           int    `json:"offset,omitempty" jsonschema:"Offset from where to start. If the offset is 0, the last 1000 lines are returned."`
-	Exclude          string `json:"exclude,omitempty" jsonschema:"Exclude lines with the given regular expression. Only use this option for logs with more than 1000 lines. Call the tool without this paramater first."`
-	Match            string `json:"match,omitempty" jsonschema:"Include only lines matchine this regular expression. Only use this option for logs with more than 1000 lines. Call the tool without this paramater first."`
-	ShowSucceeded    bool   `json:"show_succeeded,omitempty" jsonschema:"Also show succeeded logs"`
+	ProjectName      string   `json:"project_name" jsonschema:"Name of the project"`
+	PackageName      string   `json:"package_name" jsonschema:"Name of the package"`
+	Flavor           string   `json:"flavor,omitempty" jsonschema:"Flavor of the package. In most cases leave this empty, build falvors only exist if there is a _multibuild file in the source."`
+	RepositoryName   string   `json:"repository_name" jsonschema:"Repository name, use openSUSE_Tumblweed if the not requested otherwise"`
+	ArchitectureName string   `json:"architecture_name,omitempty" jsonschema:"Architecture name"`
+	NrLines          int      `json:"nr_lines,omitempty" jsonschema:"Maximum number of lines"`
+	Offset           int      `json:"offset,omitempty" jsonschema:"Line from where to start. If the offset is 0, the last nr_lines lines are returned, otherwise nr_lines lines starting at offset are returned."`
+	Exclude          string   `json:"exclude,omitempty" jsonschema:"Exclude lines with the given regular expression. Only use this option for logs with more than 1000 lines. Call the tool without this paramater first."`
+	Match            string   `json:"match,omitempty" jsonschema:"Include only lines matchine this regular expression. Only use this option for logs with more than 1000 lines. Call the tool without this paramater first."`
+	ShowSucceeded    bool     `json:"show_succeeded,omitempty" jsonschema:"Also show succeeded logs"`
+	Phases           []string `json:"phases,omitempty" jsonschema:"Only show the given phases, regardless if they succeeded, e.g. Build or RPM lint report."`
+	Flatten          bool     `json:"flatten,omitempty" jsonschema:"Return the lines of all failed phases as a single stream. Offset and nr_lines then apply to the whole stream and the total number of lines is returned, so that the log can be paged."`
+	Local            bool     `json:"local,omitempty" jsonschema:"Return the log of the last local build with run_build instead of the log of the build service. The log is kept in the workdir, so it is also available after a restart."`
+}
+
+// formatBuildLog returns the part of the log which was asked for in params.
+func (cred *OSCCredentials) formatBuildLog(log *buildlog.BuildLog, params BuildLogParam) (*mcp.CallToolResult, map[string]any, error) {
+	if len(params.Phases) > 0 {
+		var phases []buildlog.BuildPhase
+		for _, name := range params.Phases {
+			phase, err := buildlog.ParsePhase(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			phases = append(phases, phase)
+		}
+		log = log.SelectPhases(phases)
+		params.ShowSucceeded = true
+	}
+	limit := cred.MaxLogLines
+	if limit <= 0 {
+		limit = maxLines
+	}
+	nrLines := params.NrLines
+	if nrLines <= 0 || nrLines > limit {
+		nrLines = limit
+	}
+	if params.Offset < 0 {
+		return nil, nil, fmt.Errorf("offset must not be negative")
+	}
+	if params.Flatten {
+		return nil, log.FormatTail(nrLines, params.Offset, params.ShowSucceeded, params.Match, params.Exclude), nil
+	}
+	return nil, log.FormatJson(nrLines, params.Offset, params.ShowSucceeded, params.Match, params.Exclude), nil
 }
 
 func (cred *OSCCredentials) BuildLog(ctx context.Context, req *mcp.CallToolRequest, params BuildLogParam) (*mcp.CallToolResult, map[string]any, error) {
@@ -318,15 +386,17 @@ func (cred *OSCCredentials) BuildLog(ctx context.Context, req *mcp.CallToolReque
 		packageNameWithFlavor = fmt.Sprintf("%s:%s", params.PackageName, params.Flavor)
 	}
 
+	if params.Local {
+		log, err := cred.localBuildLog(params.ProjectName, params.PackageName, params.RepositoryName, params.ArchitectureName)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cred.formatBuildLog(log, params)
+	}
+
 	rawLog, err := cred.GetBuildLogRawWithProgress(ctx, params.ProjectName, params.RepositoryName, params.ArchitectureName, packageNameWithFlavor, req)
 	if err == nil {
-		log := buildlog.Parse(rawLog)
-		nrLines := params.NrLines
-		if nrLines == 0 || nrLines > maxLines {
-			nrLines = maxLines
-		}
-		result := log.FormatJson(nrLines, params.Offset, params.ShowSucceeded, params.Match, params.Exclude)
-		return nil, result, nil
+		return cred.formatBuildLog(buildlog.Parse(rawLog), params)
 	}
 
 	result := map[string]any{}
@@ -355,23 +425,11 @@ func (cred *OSCCredentials) BuildLog(ctx context.Context, req *mcp.CallToolReque
 		}
 
 		if status.Code == "unresolvable" {
-			depInfo, depErr := cred.GetBuildDepInfo(ctx, params.ProjectName, params.RepositoryName, params.ArchitectureName)
+			missingDeps, depErr := cred.getMissingDeps(ctx, params.ProjectName, params.RepositoryName, params.ArchitectureName, packageNameWithFlavor)
 			if depErr != nil {
 				return nil, nil, fmt.Errorf("package is unresolvable, but failed to get dependency info: %w", depErr)
 			}
 
-			var missingDeps []string
-			for _, p := range depInfo.Packages {
-				if p.Name == packageNameWithFlavor {
-					for _, d := range p.Deps {
-						if d.State == "missing" {
-							missingDeps = append(missingDeps, d.Name)
-						}
-					}
-					break
-				}
-			}
-
 			if len(missingDeps) > 0 {
 				return nil, nil, fmt.Errorf("package is unresolvable. Missing dependencies: %s", strings.Join(missingDeps, ", "))
 			}
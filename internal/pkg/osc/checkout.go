@@ -3,11 +3,14 @@ package osc
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -15,6 +18,7 @@ import (
 type CheckoutPackageCmd struct {
 	Project string `json:"project_name" jsonschema:"Name of the project"`
 	Package string `json:"package_name" jsonschema:"Name of the package"`
+	Expand  bool   `json:"expand,omitempty" jsonschema:"Check out the expanded sources of a linked package instead of the _link file. Files with a _service: prefix are checked out as they are stored on the server in both cases."`
 }
 
 type CheckoutPackageResult struct {
@@ -25,10 +29,25 @@ type CheckoutPackageResult struct {
 
 func (cred *OSCCredentials) CheckoutBundle(ctx context.Context, req *mcp.CallToolRequest, params CheckoutPackageCmd) (*mcp.CallToolResult, CheckoutPackageResult, error) {
 	slog.Debug("mcp tool call: CheckoutBundle", "session", req.Session.ID(), "params", params)
+	ctx, done := cred.startOperation(ctx, req, "checkout_bundle")
+	defer done()
 	if params.Project == "" || params.Package == "" {
 		return nil, CheckoutPackageResult{}, fmt.Errorf("project and package must be specified")
 	}
 
+	if cred.useInternalCommit {
+		checkoutPath, err := cred.checkoutInternal(ctx, params.Project, params.Package, params.Expand)
+		if err != nil {
+			return nil, CheckoutPackageResult{}, err
+		}
+		slog.Info("Bundle checked out successfully", "path", checkoutPath)
+		return nil, CheckoutPackageResult{
+			Path:        checkoutPath,
+			PackageName: params.Package,
+			ProjectName: params.Project,
+		}, nil
+	}
+
 	cmdline := []string{"osc"}
 	configFile, err := cred.writeTempOscConfig()
 	if err != nil {
@@ -37,7 +56,11 @@ func (cred *OSCCredentials) CheckoutBundle(ctx context.Context, req *mcp.CallToo
 		defer os.Remove(configFile)
 		cmdline = append(cmdline, "--config", configFile)
 	}
-	cmdline = append(cmdline, "checkout", params.Project, params.Package)
+	cmdline = append(cmdline, "checkout")
+	if params.Expand {
+		cmdline = append(cmdline, "-e")
+	}
+	cmdline = append(cmdline, params.Project, params.Package)
 	slog.Debug("running osc command", "command", cmdline)
 	oscCmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
 	oscCmd.Dir = cred.TempDir
@@ -58,3 +81,66 @@ func (cred *OSCCredentials) CheckoutBundle(ctx context.Context, req *mcp.CallToo
 		ProjectName: params.Project,
 	}, nil
 }
+
+// checkoutInternal checks out a package over the api without osc. It writes
+// the same .osc metadata as osc, so that the checkout can still be used with
+// osc. The files are downloaded at the srcmd5 of the listing, so that the
+// files of an expanded link match the listing.
+func (cred *OSCCredentials) checkoutInternal(ctx context.Context, project, pkg string, expand bool) (string, error) {
+	checkoutPath := filepath.Join(cred.TempDir, project, pkg)
+	if _, err := os.Stat(checkoutPath); err == nil {
+		return "", fmt.Errorf("checkout directory %s already exists", checkoutPath)
+	}
+	dir, err := cred.getRemoteDirectory(ctx, project, pkg, expand)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file list of %s/%s: %w", project, pkg, err)
+	}
+
+	sourcesDir := filepath.Join(checkoutPath, ".osc", "sources")
+	if err := os.MkdirAll(sourcesDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	tasks := make([]func() error, len(dir.Entries))
+	for i, entry := range dir.Entries {
+		tasks[i] = func() error {
+			if entry.Name != filepath.Base(entry.Name) {
+				return fmt.Errorf("invalid file name %s in file list", entry.Name)
+			}
+			fileURL := fmt.Sprintf("%s/source/%s/%s/%s", cred.GetAPiAddr(), project, pkg, url.PathEscape(entry.Name))
+			if dir.SrcMd5 != "" {
+				fileURL += "?rev=" + dir.SrcMd5
+			}
+			filePath := filepath.Join(checkoutPath, entry.Name)
+			if err := cred.downloadURL(ctx, fileURL, filePath); err != nil {
+				return fmt.Errorf("failed to download %s: %w", entry.Name, err)
+			}
+			return copyFile(filePath, filepath.Join(sourcesDir, entry.Name))
+		}
+	}
+	for _, err := range runParallel(cred.Concurrency, tasks...) {
+		if err != nil {
+			os.RemoveAll(checkoutPath)
+			return "", err
+		}
+	}
+
+	filesXML, err := xml.MarshalIndent(dir, "", "  ")
+	if err != nil {
+		os.RemoveAll(checkoutPath)
+		return "", fmt.Errorf("failed to marshal file list: %w", err)
+	}
+	metadata := map[string]string{
+		"_apiurl":         cred.GetAPiAddr() + "\n",
+		"_project":        project + "\n",
+		"_package":        pkg + "\n",
+		"_osclib_version": "1.0\n",
+		"_files":          xml.Header + string(filesXML) + "\n",
+	}
+	for name, content := range metadata {
+		if err := os.WriteFile(filepath.Join(checkoutPath, ".osc", name), []byte(content), 0644); err != nil {
+			os.RemoveAll(checkoutPath)
+			return "", fmt.Errorf("failed to write .osc/%s: %w", name, err)
+		}
+	}
+	return checkoutPath, nil
+}
@@ -0,0 +1,62 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckoutInternal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/foo":
+			assert.Equal(t, "1", r.URL.Query().Get("expand"))
+			fmt.Fprint(w, `<directory name="foo" srcmd5="0123456789abcdef0123456789abcdef">
+  <entry name="foo.spec" md5="00000000000000000000000000000000" size="10" mtime="1"/>
+  <entry name="fix.patch" md5="00000000000000000000000000000001" size="4" mtime="1"/>
+</directory>`)
+		case "/source/home:testuser/foo/foo.spec":
+			assert.Equal(t, "0123456789abcdef0123456789abcdef", r.URL.Query().Get("rev"))
+			fmt.Fprint(w, "Name: foo\n")
+		case "/source/home:testuser/foo/fix.patch":
+			fmt.Fprint(w, "fix\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, TempDir: tempDir, useInternalCommit: true}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}}
+	_, result, err := cred.CheckoutBundle(context.Background(), req, CheckoutPackageCmd{Project: "home:testuser", Package: "foo", Expand: true})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "home:testuser", "foo"), result.Path)
+
+	for file, content := range map[string]string{
+		"foo.spec":              "Name: foo\n",
+		"fix.patch":             "fix\n",
+		".osc/sources/foo.spec": "Name: foo\n",
+		".osc/_project":         "home:testuser\n",
+		".osc/_package":         "foo\n",
+		".osc/_apiurl":          server.URL + "\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(result.Path, file))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	files, err := os.ReadFile(filepath.Join(result.Path, ".osc", "_files"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(files), `<entry name="fix.patch"`)
+
+	_, _, err = cred.CheckoutBundle(context.Background(), req, CheckoutPackageCmd{Project: "home:testuser", Package: "foo", Expand: true})
+	assert.ErrorContains(t, err, "already exists")
+}
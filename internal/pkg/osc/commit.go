@@ -6,16 +6,20 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/mail"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hbollon/go-edlib"
@@ -23,18 +27,31 @@ import (
 )
 
 type CommitCmd struct {
-	Message             string   `json:"message" jsonschema:"Commit message"`
+	Message             string   `json:"message,omitempty" jsonschema:"Commit message"`
 	AddedFiles          []string `json:"added_files,omitempty" jsonschema:"Files to add before committing"`
 	RemovedFiles        []string `json:"removed_files,omitempty" jsonschema:"Files to remove before committing"`
 	Directory           string   `json:"directory" jsonschema:"Directory of the package to commit"`
 	ProjectName         string   `json:"project_name,omitempty" jsonschema:"Project name. If not provided, it will be derived from the directory path."`
 	BundleName          string   `json:"bundle_name,omitempty" jsonschema:"Bundle name also known as source package name. If not provided, it will be derived from the directory path."`
 	SkipChangesCreation bool     `json:"skip_changes,omitempty" jsonschema:"Skip the automatic update of the changes file."`
+	ChangesFromGit      bool     `json:"changes_from_git,omitempty" jsonschema:"If the package directory is a git working tree, create the changes entry from the git log since the last tag instead of the commit message."`
+	MessageFile         string   `json:"message_file,omitempty" jsonschema:"File to read the commit message from if no message is given. Relative paths are resolved against the package directory. The line breaks of the file are preserved in the changes entry."`
+	DryRun              bool     `json:"dry_run,omitempty" jsonschema:"Only return the added, changed and deleted files and the changes entry without modifying anything."`
+	SkipVerify          bool     `json:"skip_verify,omitempty" jsonschema:"Skip the comparison of the checksums of the uploaded files with the committed ones."`
+	ChangesAuthor       string   `json:"changes_author,omitempty" jsonschema:"Author of the changes entry. Defaults to the user name with a -mcpbot suffix."`
+	ChangesEmail        string   `json:"changes_email,omitempty" jsonschema:"Email address of the author of the changes entry. Defaults to the configured email address."`
+	IncludeDiff         bool     `json:"include_diff,omitempty" jsonschema:"Return the diff of the committed revision against the previous one."`
+	OnlyFiles           []string `json:"only_files,omitempty" jsonschema:"Only commit the changes of these files, other changed files are left uncommitted. The changes file with the new entry is always committed."`
 }
 
 type CommitResult struct {
-	Revision string `json:"revision"`
-	Warning  string `json:"warning,omitempty"`
+	Revision     string   `json:"revision"`
+	Warning      string   `json:"warning,omitempty"`
+	ChangesEntry string   `json:"changes_entry,omitempty"`
+	Added        []string `json:"added,omitempty"`
+	Changed      []string `json:"changed,omitempty"`
+	Deleted      []string `json:"deleted,omitempty"`
+	Diff         string   `json:"diff,omitempty"`
 }
 
 type Revision struct {
@@ -86,15 +103,80 @@ type Entry struct {
 
 func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest, params CommitCmd) (*mcp.CallToolResult, CommitResult, error) {
 	slog.Debug("mcp tool call: Commit", "session", req.Session.ID(), "params", params)
+	ctx, done := cred.startOperation(ctx, req, "commit")
+	defer done()
+	if params.Directory == "" {
+		return nil, CommitResult{}, fmt.Errorf("directory must be specified")
+	}
+	if params.Message == "" && params.MessageFile != "" {
+		message, err := readMessageFile(params.Directory, params.MessageFile)
+		if err != nil {
+			return nil, CommitResult{}, err
+		}
+		params.Message = message
+	}
 	if params.Message == "" {
 		return nil, CommitResult{}, fmt.Errorf("commit message must be specified")
 	}
-	if params.Directory == "" {
-		return nil, CommitResult{}, fmt.Errorf("directory must be specified")
+	if params.ChangesEmail != "" {
+		if addr, err := mail.ParseAddress(params.ChangesEmail); err != nil || addr.Address != params.ChangesEmail {
+			return nil, CommitResult{}, fmt.Errorf("invalid changes email address: %s", params.ChangesEmail)
+		}
 	}
 	progressToken := req.Params.GetProgressToken()
 
-	if !cred.useInternalCommit {
+	projectName := params.ProjectName
+	bundleName := params.BundleName
+	if projectName == "" {
+		projectName = filepath.Base(filepath.Dir(params.Directory))
+	}
+	if bundleName == "" {
+		bundleName = filepath.Base(params.Directory)
+	}
+	if projectName == "" || bundleName == "" {
+		return nil, CommitResult{}, fmt.Errorf("could not determine project and package name from directory: %s", params.Directory)
+	}
+	if scmsync, err := cred.getScmSync(ctx, projectName, bundleName); err != nil {
+		slog.Warn("could not check for scmsync", "error", err)
+	} else if scmsync != "" {
+		return nil, CommitResult{}, fmt.Errorf("%w: %s", ErrScmSync, scmsync)
+	}
+
+	// the changes entry is written for both, osc and the internal commit
+	var changesEntry string
+	var changesFileName string
+	if !params.SkipChangesCreation {
+		changesFile := findChangesFile(params.Directory, bundleName)
+		if changesFile != "" {
+			message := params.Message
+			if params.ChangesFromGit {
+				gitMessage, err := gitLogMessage(ctx, params.Directory, changesFile)
+				if err != nil {
+					slog.Warn("could not create changes from git log, using commit message", "error", err)
+				} else {
+					message = gitMessage
+				}
+			}
+			author := cred.Name + "-mcpbot"
+			if params.ChangesAuthor != "" {
+				author = params.ChangesAuthor
+			}
+			email := cred.EMail
+			if params.ChangesEmail != "" {
+				email = params.ChangesEmail
+			}
+			changesEntry = createChangesEntry(message, author, email)
+			changesFileName = filepath.Base(changesFile)
+		}
+		if changesFile != "" && !params.DryRun {
+			if err := prependChangesEntry(changesFile, changesEntry); err != nil {
+				return nil, CommitResult{}, err
+			}
+		}
+	}
+
+	// a dry run always compares the files itself, as osc can't preview a commit
+	if !cred.useInternalCommit && !params.DryRun {
 		baseCmdline := []string{"osc"}
 		configFile, err := cred.writeTempOscConfig()
 		if err != nil {
@@ -115,23 +197,18 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		}
 		slog.Debug("osc status finished successfully", slog.String("command", oscStatusCmd.String()), "output", string(statusOutput))
 
-		var filesToAdd []string
-		var filesToRemove []string
-		statusScanner := bufio.NewScanner(bytes.NewReader(statusOutput))
-		for statusScanner.Scan() {
-			line := statusScanner.Text()
-			parts := strings.Fields(line)
-			if len(parts) < 2 {
-				continue
-			}
-			status := parts[0]
-			fileName := strings.Join(parts[1:], " ")
-			switch status {
-			case "?":
-				filesToAdd = append(filesToAdd, fileName)
-			case "D":
-				filesToRemove = append(filesToRemove, fileName)
-			}
+		filesToAdd, filesToRemove, statusResult := parseOscStatus(statusOutput)
+		onlyFiles := params.OnlyFiles
+		if len(onlyFiles) > 0 && changesFileName != "" && !slices.Contains(onlyFiles, changesFileName) {
+			onlyFiles = append(slices.Clone(onlyFiles), changesFileName)
+		}
+		if len(onlyFiles) > 0 {
+			only := fileSet(onlyFiles)
+			filesToAdd = filterFiles(filesToAdd, only)
+			filesToRemove = filterFiles(filesToRemove, only)
+			statusResult.Added = filterFiles(statusResult.Added, only)
+			statusResult.Changed = filterFiles(statusResult.Changed, only)
+			statusResult.Deleted = filterFiles(statusResult.Deleted, only)
 		}
 
 		if len(filesToAdd) > 0 {
@@ -163,6 +240,7 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		}
 
 		cmdline := append(baseCmdline, "commit", "-m", params.Message)
+		cmdline = append(cmdline, onlyFiles...)
 
 		oscCmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
 		oscCmd.Dir = params.Directory
@@ -220,61 +298,14 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 			}
 		}
 
-		return nil, CommitResult{Revision: rev}, nil
-	}
-
-	projectName := params.ProjectName
-	bundleName := params.BundleName
-	if projectName == "" {
-		projectName = filepath.Base(filepath.Dir(params.Directory))
-	}
-	if bundleName == "" {
-		bundleName = filepath.Base(params.Directory)
-	}
-	if projectName == "" || bundleName == "" {
-		return nil, CommitResult{}, fmt.Errorf("could not determine project and package name from directory: %s", params.Directory)
-	}
-	if !params.SkipChangesCreation {
-		var changesFile string
-		if changesFiles, _ := filepath.Glob(path.Join(params.Directory, "*changes")); len(changesFiles) > 0 {
-			// only create a changes file if we find a spec file, ergo it's a rpm
-			// do some funky math to find the best matching changes file of pkg
-			if len(changesFiles) > 1 {
-				changesFile, _ = edlib.FuzzySearch(bundleName, changesFiles, edlib.Levenshtein)
-			} else {
-				changesFile = changesFiles[0]
-			}
-			// no changes file, let's create one based on a spec files
-			if changesFile == "" {
-				if specFiles, _ := filepath.Glob(path.Join(params.Directory, "*spec")); len(specFiles) > 0 {
-					if len(specFiles) > 1 {
-						changesFile, _ = edlib.FuzzySearch(bundleName, specFiles, edlib.Levenshtein)
-					} else {
-						changesFile = specFiles[0]
-					}
-					changesFile = strings.TrimSuffix(changesFile, ".spec") + ".changes"
-				}
-			}
-		}
-		if changesFile != "" {
-
-			changesEntry := createChangesEntry(params.Message, cred.Name+"-mcpbot", cred.EMail)
-
-			content, err := os.ReadFile(changesFile)
-			if err != nil {
-				if !os.IsNotExist(err) {
-					return nil, CommitResult{}, fmt.Errorf("failed to read changes file %s: %w", changesFile, err)
-				}
-				content = []byte{}
-			}
-
-			newContent := append([]byte(changesEntry), content...)
-			err = os.WriteFile(changesFile, newContent, 0644)
-			if err != nil {
-				return nil, CommitResult{}, fmt.Errorf("failed to write to changes file %s: %w", changesFile, err)
-			}
+		statusResult.Revision = rev
+		statusResult.ChangesEntry = changesEntry
+		if params.IncludeDiff && rev != "" {
+			statusResult.Diff, statusResult.Warning = cred.commitDiff(ctx, projectName, bundleName, rev, statusResult.Warning)
 		}
+		return nil, statusResult, nil
 	}
+
 	// get the remote files so that we know what to commit
 	if progressToken != nil {
 		if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
@@ -311,6 +342,7 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 	var newFiles []string
 	var deletedFiles []string
 	localFileMap := make(map[string]bool)
+	localMd5 := make(map[string]string)
 	removedFileMap := make(map[string]bool)
 	for _, f := range params.RemovedFiles {
 		removedFileMap[f] = true
@@ -335,6 +367,7 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 			return nil, CommitResult{}, fmt.Errorf("failed to calculate md5 for %s: %w", fileName, err)
 		}
 
+		localMd5[fileName] = hash
 		remoteEntry, exists := remoteFileMap[fileName]
 		if !exists {
 			newFiles = append(newFiles, fileName)
@@ -349,23 +382,40 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		}
 	}
 
+	// with only_files the other files keep their remote state
+	var only map[string]bool
+	if len(params.OnlyFiles) > 0 {
+		only = fileSet(params.OnlyFiles)
+		if changesFileName != "" {
+			only[changesFileName] = true
+		}
+		newFiles = filterFiles(newFiles, only)
+		changedFiles = filterFiles(changedFiles, only)
+		deletedFiles = filterFiles(deletedFiles, only)
+	}
+
+	if params.DryRun {
+		// the changes file will be modified by the commit
+		if changesFileName != "" && !slices.Contains(newFiles, changesFileName) && !slices.Contains(changedFiles, changesFileName) {
+			if _, exists := remoteFileMap[changesFileName]; exists {
+				changedFiles = append(changedFiles, changesFileName)
+			} else {
+				newFiles = append(newFiles, changesFileName)
+			}
+		}
+		return nil, CommitResult{
+			ChangesEntry: changesEntry,
+			Added:        newFiles,
+			Changed:      changedFiles,
+			Deleted:      deletedFiles,
+		}, nil
+	}
+
 	filesToUpload := append(newFiles, changedFiles...)
 	if len(filesToUpload) > 0 {
 		slog.Debug("Uploading changed files", "files", filesToUpload)
-		for _, fileName := range filesToUpload {
-			if progressToken != nil {
-				if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
-					ProgressToken: progressToken,
-					Message:       "Uploading " + fileName,
-				}); err != nil {
-					slog.Warn("failed to send progress notification", "error", err)
-				}
-			}
-			filePath := filepath.Join(params.Directory, fileName)
-			err := cred.uploadFile(ctx, projectName, bundleName, fileName, filePath)
-			if err != nil {
-				return nil, CommitResult{}, fmt.Errorf("failed to upload file %s: %w", fileName, err)
-			}
+		if err := cred.uploadFiles(ctx, req, projectName, bundleName, params.Directory, filesToUpload); err != nil {
+			return nil, CommitResult{}, err
 		}
 	} else {
 		slog.Debug("No changed files to upload")
@@ -396,6 +446,9 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		if strings.HasPrefix(fileName, ".") {
 			continue
 		}
+		if only != nil && !only[fileName] {
+			continue
+		}
 		filePath := filepath.Join(params.Directory, fileName)
 		info, err := file.Info()
 		if err != nil {
@@ -416,6 +469,8 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 	for _, entry := range remoteFiles.Entries {
 		if strings.HasPrefix(entry.Name, "_service:") || entry.Name == "_link" {
 			commitDir.Entries = append(commitDir.Entries, entry)
+		} else if only != nil && !only[entry.Name] {
+			commitDir.Entries = append(commitDir.Entries, entry)
 		}
 	}
 
@@ -437,6 +492,17 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		return nil, CommitResult{}, fmt.Errorf("failed to commit changes: %w", err)
 	}
 
+	var newRemoteFiles *Directory
+	var warning string
+	if !params.SkipVerify && len(filesToUpload) > 0 {
+		newRemoteFiles, err = cred.getRemoteFileList(ctx, projectName, bundleName)
+		if err != nil {
+			warning = fmt.Sprintf("could not verify the committed files: %v", err)
+		} else {
+			warning = verifyUploads(newRemoteFiles, filesToUpload, localMd5)
+		}
+	}
+
 	// Update .osc/_files cache
 	oscDir := filepath.Join(params.Directory, ".osc")
 	if _, err := os.Stat(oscDir); !os.IsNotExist(err) {
@@ -449,7 +515,10 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 			}
 		}
 		slog.Debug("Updating .osc/_files cache")
-		newRemoteFiles, err := cred.getRemoteFileList(ctx, projectName, bundleName)
+		var err error
+		if newRemoteFiles == nil {
+			newRemoteFiles, err = cred.getRemoteFileList(ctx, projectName, bundleName)
+		}
 		if err != nil {
 			// Don't fail the whole commit, just warn. The cache can be updated later.
 			slog.Warn("failed to get updated remote file list, .osc/_files not updated", "error", err)
@@ -506,6 +575,17 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 				sourceWdPath := filepath.Join(params.Directory, entry.Name)
 				sourceCachePath := filepath.Join(sourcesDir, entry.Name)
 
+				if _, existed := remoteFileMap[entry.Name]; existed && only != nil && !only[entry.Name] {
+					// The file was left out of the commit, so .osc/sources keeps the
+					// content of the server and the working dir keeps the local changes
+					if _, err := os.Stat(sourceCachePath); os.IsNotExist(err) {
+						if err := cred.downloadFile(ctx, projectName, bundleName, entry.Name, sourceCachePath); err != nil {
+							slog.Warn("failed to download file to .osc/sources", "file", entry.Name, "error", err)
+						}
+					}
+					continue
+				}
+
 				if _, err := os.Stat(sourceWdPath); !os.IsNotExist(err) {
 					// File exists in working dir, copy it to .osc/sources
 					slog.Debug("Copying file to .osc/sources", "file", entry.Name)
@@ -530,23 +610,186 @@ func (cred *OSCCredentials) Commit(ctx context.Context, req *mcp.CallToolRequest
 		}
 	}
 
-	return nil, CommitResult{Revision: revision.Rev}, nil
+	var diff string
+	if params.IncludeDiff {
+		diff, warning = cred.commitDiff(ctx, projectName, bundleName, revision.Rev, warning)
+	}
+
+	return nil, CommitResult{
+		Revision:     revision.Rev,
+		Warning:      warning,
+		Diff:         diff,
+		ChangesEntry: changesEntry,
+		Added:        newFiles,
+		Changed:      changedFiles,
+		Deleted:      deletedFiles,
+	}, nil
+}
+
+func fileSet(files []string) map[string]bool {
+	set := make(map[string]bool, len(files))
+	for _, f := range files {
+		set[f] = true
+	}
+	return set
+}
+
+// filterFiles returns the files which are in set.
+func filterFiles(files []string, set map[string]bool) []string {
+	var filtered []string
+	for _, f := range files {
+		if set[f] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// commitDiff returns the diff of the committed revision against the previous
+// one. As the commit already succeeded, a failure is only added to warning.
+func (cred *OSCCredentials) commitDiff(ctx context.Context, project, pkg, rev, warning string) (string, string) {
+	diff, _, err := cred.getSourceDiff(ctx, project, pkg, "", rev, false)
+	if err != nil {
+		slog.Warn("failed to get diff of commit", "revision", rev, "error", err)
+		if warning != "" {
+			warning += "; "
+		}
+		return "", warning + fmt.Sprintf("could not get the diff of revision %s: %v", rev, err)
+	}
+	return diff, warning
+}
+
+// prependChangesEntry adds entry at the top of changesFile, which is created
+// if it doesn't exist. A byte order mark is removed and CRLF line endings are
+// converted, so that the file has consistent line endings afterwards.
+func prependChangesEntry(changesFile, entry string) error {
+	content, err := os.ReadFile(changesFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read changes file %s: %w", changesFile, err)
+	}
+	if isBinary(content) {
+		return fmt.Errorf("changes file %s is not a text file", changesFile)
+	}
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if err := os.WriteFile(changesFile, append([]byte(entry), content...), 0644); err != nil {
+		return fmt.Errorf("failed to write to changes file %s: %w", changesFile, err)
+	}
+	return nil
+}
+
+// findChangesFile returns the changes file for the commit entry. The changes
+// file with the basename of the spec file of the bundle is preferred, so that
+// the changes of subpackages like foo-doc.changes aren't picked. Only if there
+// is no such file the best matching one is used.
+func findChangesFile(dir, bundleName string) string {
+	changesFiles, _ := filepath.Glob(path.Join(dir, "*changes"))
+	if len(changesFiles) == 0 {
+		return ""
+	}
+	specFiles, _ := filepath.Glob(path.Join(dir, "*spec"))
+	spec := path.Join(dir, bundleName+".spec")
+	if !slices.Contains(specFiles, spec) && len(specFiles) == 1 {
+		spec = specFiles[0]
+	}
+	if changesFile := strings.TrimSuffix(spec, ".spec") + ".changes"; slices.Contains(changesFiles, changesFile) {
+		return changesFile
+	}
+	// do some funky math to find the best matching changes file of pkg
+	var changesFile string
+	if len(changesFiles) > 1 {
+		changesFile, _ = edlib.FuzzySearch(bundleName, changesFiles, edlib.Levenshtein)
+	} else {
+		changesFile = changesFiles[0]
+	}
+	// no changes file, let's create one based on a spec files
+	if changesFile == "" && len(specFiles) > 0 {
+		if len(specFiles) > 1 {
+			changesFile, _ = edlib.FuzzySearch(bundleName, specFiles, edlib.Levenshtein)
+		} else {
+			changesFile = specFiles[0]
+		}
+		changesFile = strings.TrimSuffix(changesFile, ".spec") + ".changes"
+	}
+	return changesFile
+}
+
+// verifyUploads compares the md5 sums of the uploaded files with the ones of
+// the committed files and returns a warning listing the mismatches.
+func verifyUploads(remote *Directory, uploaded []string, localMd5 map[string]string) string {
+	remoteMd5 := make(map[string]string)
+	for _, entry := range remote.Entries {
+		remoteMd5[entry.Name] = entry.Md5
+	}
+	var mismatches []string
+	for _, fileName := range uploaded {
+		if remoteMd5[fileName] != localMd5[fileName] {
+			mismatches = append(mismatches, fileName)
+		}
+	}
+	if len(mismatches) == 0 {
+		return ""
+	}
+	slog.Warn("committed files differ from the uploaded ones", "files", mismatches)
+	return fmt.Sprintf("the committed files differ from the local ones, upload them again: %s", strings.Join(mismatches, ", "))
+}
+
+// parseOscStatus parses the output of 'osc status' and returns the untracked
+// files which have to be added, the missing files which have to be removed and
+// the files which will be added, changed or deleted by the commit.
+func parseOscStatus(statusOutput []byte) (filesToAdd, filesToRemove []string, result CommitResult) {
+	statusScanner := bufio.NewScanner(bytes.NewReader(statusOutput))
+	for statusScanner.Scan() {
+		line := statusScanner.Text()
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+		status := parts[0]
+		fileName := strings.Join(parts[1:], " ")
+		switch status {
+		case "?":
+			filesToAdd = append(filesToAdd, fileName)
+			result.Added = append(result.Added, fileName)
+		case "A":
+			result.Added = append(result.Added, fileName)
+		case "M", "R":
+			result.Changed = append(result.Changed, fileName)
+		case "D":
+			filesToRemove = append(filesToRemove, fileName)
+			result.Deleted = append(result.Deleted, fileName)
+		}
+	}
+	return filesToAdd, filesToRemove, result
 }
 
 func (cred *OSCCredentials) getRemoteFileList(ctx context.Context, project, pkg string) (*Directory, error) {
+	dir, err := cred.getRemoteDirectory(ctx, project, pkg, false)
+	if errors.Is(err, ErrBundleOrProjectNotFound) {
+		return &Directory{}, nil
+	}
+	return dir, err
+}
+
+// getRemoteDirectory returns the file list of a package, or of the expanded
+// sources of a link if expand is set.
+func (cred *OSCCredentials) getRemoteDirectory(ctx context.Context, project, pkg string, expand bool) (*Directory, error) {
 	url := fmt.Sprintf("%s/source/%s/%s", cred.GetAPiAddr(), project, pkg)
+	if expand {
+		url += "?expand=1"
+	}
 	req, err := cred.buildRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return &Directory{}, nil
+		return nil, ErrBundleOrProjectNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -592,24 +835,67 @@ func copyFile(src, dst string) error {
 	return err
 }
 
+// uploadFiles uploads the files of dir with at most MaxUploadConcurrency
+// uploads at the same time. The first failed upload cancels the others and
+// its error is returned.
+func (cred *OSCCredentials) uploadFiles(ctx context.Context, req *mcp.CallToolRequest, project, pkg, dir string, fileNames []string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	progressToken := req.Params.GetProgressToken()
+	var mu sync.Mutex
+	var firstErr error
+	tasks := make([]func() error, len(fileNames))
+	for i, fileName := range fileNames {
+		tasks[i] = func() error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if progressToken != nil {
+				if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					ProgressToken: progressToken,
+					Message:       "Uploading " + fileName,
+				}); err != nil {
+					slog.Warn("failed to send progress notification", "error", err)
+				}
+			}
+			err := cred.uploadFile(ctx, project, pkg, fileName, filepath.Join(dir, fileName))
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to upload file %s: %w", fileName, err)
+					cancel()
+				}
+				mu.Unlock()
+			}
+			return err
+		}
+	}
+	runParallel(cred.MaxUploadConcurrency, tasks...)
+	return firstErr
+}
+
 func (cred *OSCCredentials) uploadFile(ctx context.Context, project, pkg, fileName, filePath string) error {
-	file, err := os.Open(filePath)
+	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	fileInfo, _ := file.Stat()
 	url := fmt.Sprintf("%s/source/%s/%s/%s", cred.GetAPiAddr(), project, pkg, fileName)
 	slog.Debug("Uploading file", "file", fileName, "size", fileInfo.Size(), "project", project, "package", pkg)
 
-	req, err := cred.buildRequest(ctx, "PUT", url, file)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/octet-stream")
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := cred.doWithRetry(ctx, func() (*http.Request, error) {
+		// the client closes the file after sending it
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		req, err := cred.buildRequest(ctx, "PUT", url, file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		return req, nil
+	})
 	if err != nil {
 		slog.Error("File upload failed", "file", fileName, "error", err)
 		return err
@@ -625,31 +911,135 @@ func (cred *OSCCredentials) uploadFile(ctx context.Context, project, pkg, fileNa
 	return nil
 }
 
+var retryDelay = time.Second
+
+// defaultRetries is used if cred.Retries is 0, a negative value disables the
+// retries.
+const defaultRetries = 3
+
+// doWithRetry sends the request created by newRequest and retries network
+// errors and server errors up to cred.Retries times with an exponential
+// backoff. Client errors are never retried. newRequest is called for every
+// attempt, as the body of a sent request can't be reused.
+func (cred *OSCCredentials) doWithRetry(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	retries := cred.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := cred.do(req)
+		if attempt >= retries || ctx.Err() != nil {
+			return resp, err
+		}
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if err == nil {
+			slog.Debug("retrying request", "url", req.URL, "attempt", attempt+1, "status", resp.Status)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			slog.Debug("retrying request", "url", req.URL, "attempt", attempt+1, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryDelay << attempt):
+		}
+	}
+}
+
+const downloadRetries = 3
+
+var downloadRetryDelay = time.Second
+
+// downloadFile downloads a source file to destinationPath. The file is written
+// to a temporary file first, which is only renamed on success. Transient
+// failures are retried, and an interrupted download is resumed if the server
+// supports ranges.
 func (cred *OSCCredentials) downloadFile(ctx context.Context, project, pkg, fileName, destinationPath string) error {
 	url := fmt.Sprintf("%s/source/%s/%s/%s", cred.GetAPiAddr(), project, pkg, fileName)
+	return cred.downloadURL(ctx, url, destinationPath)
+}
+
+// downloadURL downloads url to destinationPath like downloadFile.
+func (cred *OSCCredentials) downloadURL(ctx context.Context, url, destinationPath string) error {
+	partPath := destinationPath + ".part"
+	defer os.Remove(partPath)
+
+	var lastErr error
+	for attempt := 0; attempt < downloadRetries; attempt++ {
+		if attempt > 0 {
+			slog.Debug("retrying download", "url", url, "attempt", attempt, "error", lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(downloadRetryDelay * time.Duration(attempt)):
+			}
+		}
+		retry, err := cred.downloadFileAttempt(ctx, url, partPath)
+		if err == nil {
+			return os.Rename(partPath, destinationPath)
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// downloadFileAttempt downloads url to partPath and resumes from the size of
+// partPath if it exists. It returns if the failure can be retried.
+func (cred *OSCCredentials) downloadFileAttempt(ctx context.Context, url, partPath string) (bool, error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
 	req, err := cred.buildRequest(ctx, "GET", url, nil)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
-		return err
+		return ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// server doesn't support ranges, start from the beginning
+	default:
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to download file: status %s, body: %s", resp.Status, string(body))
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			os.Remove(partPath)
+		}
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable
+		return retry, fmt.Errorf("failed to download file: status %s, body: %s", resp.Status, string(body))
 	}
 
-	outFile, err := os.Create(destinationPath)
+	outFile, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer outFile.Close()
 
-	_, err = io.Copy(outFile, resp.Body)
-	return err
+	if _, err = io.Copy(outFile, resp.Body); err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return false, outFile.Close()
 }
 
 func (cred *OSCCredentials) commitFiles(ctx context.Context, project, pkg, message string, xmlData []byte) (*Revision, error) {
@@ -658,13 +1048,14 @@ func (cred *OSCCredentials) commitFiles(ctx context.Context, project, pkg, messa
 	slog.Debug("Committing to OBS", "url", url)
 	slog.Info("Committing changes", "project", project, "package", pkg)
 
-	req, err := cred.buildRequest(ctx, "POST", url, bytes.NewReader(xmlData))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/xml")
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := cred.doWithRetry(ctx, func() (*http.Request, error) {
+		req, err := cred.buildRequest(ctx, "POST", url, bytes.NewReader(xmlData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/xml")
+		return req, nil
+	})
 	if err != nil {
 		slog.Error("Commit request failed", "project", project, "package", pkg, "error", err)
 		return nil, err
@@ -690,12 +1081,84 @@ func createChangesEntry(message, userName, userEmail string) string {
 	b.WriteString(time.Now().UTC().Format("Mon Jan 02 15:04:05 MST 2006"))
 	b.WriteString(fmt.Sprintf(" - %s <%s>\n\n", userName, userEmail))
 
+	// blank lines separate paragraphs, only leading and trailing ones are dropped
 	lines := strings.Split(message, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
 	for _, line := range lines {
-		if trimmedLine := strings.TrimSpace(line); trimmedLine != "" {
+		trimmedLine := strings.TrimSpace(line)
+		switch {
+		case trimmedLine == "":
+			b.WriteString("\n")
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, " "), strings.HasPrefix(line, "\t"):
+			// already formatted entry or continuation line
+			b.WriteString(strings.TrimRight(line, " \t") + "\n")
+		default:
 			b.WriteString(fmt.Sprintf("- %s\n", trimmedLine))
 		}
 	}
 	b.WriteString("\n")
 	return b.String()
 }
+
+// readMessageFile reads a commit message from messageFile, which is resolved
+// relative to the package directory dir.
+func readMessageFile(dir, messageFile string) (string, error) {
+	if !filepath.IsAbs(messageFile) {
+		messageFile = filepath.Join(dir, messageFile)
+	}
+	content, err := os.ReadFile(messageFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message file %s: %w", messageFile, err)
+	}
+	message := strings.TrimRight(string(content), "\n")
+	if strings.TrimSpace(message) == "" {
+		return "", fmt.Errorf("message file %s is empty", messageFile)
+	}
+	return message, nil
+}
+
+// gitLogMessage returns the subjects of the git commits since the last tag of
+// the working tree in dir, one per line. If there is no tag, the commits since
+// the last modification of changesFile are used.
+func gitLogMessage(ctx context.Context, dir, changesFile string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return "", fmt.Errorf("%s is not a git working tree", dir)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is not available: %w", err)
+	}
+
+	revRange := ""
+	tagCmd := exec.CommandContext(ctx, "git", "describe", "--tags", "--abbrev=0")
+	tagCmd.Dir = dir
+	if out, err := tagCmd.Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+		revRange = strings.TrimSpace(string(out)) + "..HEAD"
+	} else if changesFile != "" {
+		lastCmd := exec.CommandContext(ctx, "git", "log", "-1", "--format=%H", "--", filepath.Base(changesFile))
+		lastCmd.Dir = dir
+		if out, err := lastCmd.Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+			revRange = strings.TrimSpace(string(out)) + "..HEAD"
+		}
+	}
+
+	args := []string{"log", "--no-merges", "--format=%s"}
+	if revRange != "" {
+		args = append(args, revRange)
+	}
+	logCmd := exec.CommandContext(ctx, "git", args...)
+	logCmd.Dir = dir
+	out, err := logCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run '%s': %w", logCmd.String(), err)
+	}
+	message := strings.TrimSpace(string(out))
+	if message == "" {
+		return "", fmt.Errorf("no git commits found in %s", dir)
+	}
+	return message, nil
+}
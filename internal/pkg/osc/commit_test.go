@@ -0,0 +1,611 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitFileLists(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.spec"), []byte("Name: testpackage\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unchanged.txt"), []byte("unchanged\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "new.patch"), []byte("new\n"), 0644))
+	unchangedMd5, err := fileMD5(filepath.Join(dir, "unchanged.txt"))
+	assert.NoError(t, err)
+
+	var mu sync.Mutex
+	var uploaded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `<directory name="testpackage" rev="1">
+  <entry name="testpackage.spec" md5="00000000000000000000000000000000" size="1" mtime="1"/>
+  <entry name="unchanged.txt" md5="%s" size="10" mtime="1"/>
+  <entry name="old.tar.gz" md5="11111111111111111111111111111111" size="1" mtime="1"/>
+</directory>`, unchangedMd5)
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/_meta"):
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<package name="testpackage" project="home:testuser"><title/><description/></package>`)
+		case r.Method == "PUT":
+			mu.Lock()
+			uploaded = append(uploaded, filepath.Base(r.URL.Path))
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "POST" && r.URL.Query().Get("cmd") == "commit":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<revision rev="2"/>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:              "testuser",
+		Passwd:            "testpassword",
+		Apiaddr:           server.URL,
+		useInternalCommit: true,
+	}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, result, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:             "update",
+		Directory:           dir,
+		SkipChangesCreation: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", result.Revision)
+	assert.Equal(t, []string{"new.patch"}, result.Added)
+	assert.Equal(t, []string{"testpackage.spec"}, result.Changed)
+	assert.Equal(t, []string{"old.tar.gz"}, result.Deleted)
+	assert.ElementsMatch(t, []string{"new.patch", "testpackage.spec"}, uploaded)
+	// the test server doesn't store the uploads
+	assert.Contains(t, result.Warning, "new.patch, testpackage.spec")
+}
+
+func TestCommitScmSync(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage/_meta":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/_meta":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<project name="home:testuser"><title/><description/><scmsync>https://src.example.org/testuser/project</scmsync></project>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:              "testuser",
+		Passwd:            "testpassword",
+		Apiaddr:           server.URL,
+		useInternalCommit: true,
+	}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, _, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:             "update",
+		Directory:           dir,
+		SkipChangesCreation: true,
+	})
+	assert.ErrorIs(t, err, ErrScmSync)
+	assert.Contains(t, err.Error(), "https://src.example.org/testuser/project")
+}
+
+func TestParseOscStatus(t *testing.T) {
+	status := []byte("?    new.patch\nA    added.txt\nM    testpackage.spec\nD    old.tar.gz\n     unchanged.txt\n")
+	filesToAdd, filesToRemove, result := parseOscStatus(status)
+	assert.Equal(t, []string{"new.patch"}, filesToAdd)
+	assert.Equal(t, []string{"old.tar.gz"}, filesToRemove)
+	assert.Equal(t, []string{"new.patch", "added.txt"}, result.Added)
+	assert.Equal(t, []string{"testpackage.spec"}, result.Changed)
+	assert.Equal(t, []string{"old.tar.gz"}, result.Deleted)
+}
+
+func TestReadMessageFile(t *testing.T) {
+	dir := t.TempDir()
+	message := "- Update to version 1.2\n  * fix crash on startup\n- Drop obsolete patch\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "message.txt"), []byte(message), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "empty.txt"), []byte("\n\n"), 0644))
+
+	content, err := readMessageFile(dir, "message.txt")
+	assert.NoError(t, err)
+	entry := createChangesEntry(content, "testuser", "testuser@example.com")
+	assert.Contains(t, entry, "\n\n- Update to version 1.2\n  * fix crash on startup\n- Drop obsolete patch\n\n")
+
+	// paragraphs are kept, blank lines around the message are dropped
+	message = "\n\n- Update to version 1.3\n  * new upstream release\n\n- Add patch for CVE-2024-1234\n\n\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "paragraphs.txt"), []byte(message), 0644))
+	content, err = readMessageFile(dir, "paragraphs.txt")
+	assert.NoError(t, err)
+	entry = createChangesEntry(content, "testuser", "testuser@example.com")
+	assert.True(t, strings.HasSuffix(entry, "<testuser@example.com>\n\n- Update to version 1.3\n  * new upstream release\n\n- Add patch for CVE-2024-1234\n\n"), entry)
+
+	_, err = readMessageFile(dir, "empty.txt")
+	assert.Error(t, err)
+	_, err = readMessageFile(dir, "missing.txt")
+	assert.Error(t, err)
+}
+
+func TestGitLogMessage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	changesFile := filepath.Join(dir, "testpackage.changes")
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=testuser", "-c", "user.email=testuser@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	commit := func(file, content, subject string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+		git("add", file)
+		git("commit", "-q", "-m", subject)
+	}
+
+	_, err := gitLogMessage(context.Background(), dir, changesFile)
+	assert.ErrorContains(t, err, "not a git working tree")
+
+	git("init", "-q")
+	commit("testpackage.changes", "initial\n", "Add changes")
+	commit("testpackage.spec", "Version: 1.1\n", "Fix the build with gcc 15")
+	commit("testpackage.spec", "Version: 1.2\n", "Update to version 1.2")
+
+	// the commits since the last change of the changes file
+	message, err := gitLogMessage(context.Background(), dir, changesFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "Update to version 1.2\nFix the build with gcc 15", message)
+	entry := createChangesEntry(message, "testuser", "testuser@example.com")
+	assert.True(t, strings.HasSuffix(entry, "<testuser@example.com>\n\n- Update to version 1.2\n- Fix the build with gcc 15\n\n"), entry)
+
+	// the changes file is newer than the commits, so there is nothing to add
+	commit("testpackage.changes", "updated\n", "Update changes")
+	_, err = gitLogMessage(context.Background(), dir, changesFile)
+	assert.ErrorContains(t, err, "no git commits found")
+
+	// a tag takes precedence over the changes file
+	git("tag", "v1.2")
+	commit("testpackage.spec", "Version: 1.3\n", "Update to version 1.3")
+	message, err = gitLogMessage(context.Background(), dir, changesFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "Update to version 1.3", message)
+}
+
+// fakeOsc puts an osc script into PATH which prints status for osc status
+// and commits revision 7. The arguments of the calls are written to the
+// returned file.
+func fakeOsc(t *testing.T, status string) string {
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := fmt.Sprintf(`#!/bin/sh
+[ "$1" = "--config" ] && shift 2
+echo "$@" >> %s
+case "$1" in
+status) printf '%%s' '%s' ;;
+commit) echo "Committed revision 7." ;;
+esac
+`, argsFile, status)
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "osc"), []byte(script), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestCommitOscChangesFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=testuser", "-c", "user.email=testuser@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	commit := func(file, content, subject string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+		git("add", file)
+		git("commit", "-q", "-m", subject)
+	}
+	git("init", "-q")
+	commit("testpackage.changes", "old entry\n", "Add changes")
+	commit("testpackage.spec", "Version: 1.1\n", "Fix the build with gcc 15")
+	commit("testpackage.spec", "Version: 1.2\n", "Update to version 1.2")
+
+	argsFile := fakeOsc(t, "M    testpackage.spec\nM    testpackage.changes\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<package name="testpackage" project="home:testuser"><title/><description/></package>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", EMail: "testuser@example.com", Apiaddr: server.URL}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, result, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:        "update",
+		Directory:      dir,
+		ChangesFromGit: true,
+		OnlyFiles:      []string{"testpackage.spec"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "7", result.Revision)
+	assert.Contains(t, result.ChangesEntry, "\n\n- Update to version 1.2\n- Fix the build with gcc 15\n\n")
+	assert.Contains(t, result.ChangesEntry, " - testuser-mcpbot <testuser@example.com>\n")
+	assert.Equal(t, []string{"testpackage.spec", "testpackage.changes"}, result.Changed)
+	content, err := os.ReadFile(filepath.Join(dir, "testpackage.changes"))
+	assert.NoError(t, err)
+	assert.Equal(t, result.ChangesEntry+"old entry\n", string(content))
+	args, err := os.ReadFile(argsFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "status\ncommit -m update testpackage.spec testpackage.changes\n", string(args))
+}
+
+func TestCommitOscChangesAuthor(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.changes"), []byte("old entry\n"), 0644))
+	argsFile := fakeOsc(t, "M    testpackage.changes\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<package name="testpackage" project="home:testuser"><title/><description/></package>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", EMail: "testuser@example.com", Apiaddr: server.URL}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, result, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:       "Fix the build",
+		Directory:     dir,
+		ChangesAuthor: "Jane Packager",
+		ChangesEmail:  "jane@example.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "7", result.Revision)
+	assert.Contains(t, result.ChangesEntry, " - Jane Packager <jane@example.com>\n\n- Fix the build\n")
+	content, err := os.ReadFile(filepath.Join(dir, "testpackage.changes"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), result.ChangesEntry))
+	args, err := os.ReadFile(argsFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "status\ncommit -m Fix the build\n", string(args))
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	downloadRetryDelay = 0
+	content := strings.Repeat("0123456789", 100)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+		ranges = append(ranges, rangeHeader)
+		if rangeHeader == "" {
+			// send half of the file and drop the connection
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, content[:500])
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(t, err)
+			conn.Close()
+			return
+		}
+		var start int
+		fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		io.WriteString(w, content[start:])
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+	dest := filepath.Join(t.TempDir(), "source.tar")
+	err := cred.downloadFile(context.Background(), "home:testuser", "testpackage", "source.tar", dest)
+	assert.NoError(t, err)
+	downloaded, err := os.ReadFile(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(downloaded))
+	assert.Equal(t, []string{"", "bytes=500-"}, ranges)
+	_, err = os.Stat(dest + ".part")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadFileFailure(t *testing.T) {
+	downloadRetryDelay = 0
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+	dest := filepath.Join(t.TempDir(), "source.tar")
+	err := cred.downloadFile(context.Background(), "home:testuser", "testpackage", "source.tar", dest)
+	assert.Error(t, err)
+	assert.Equal(t, downloadRetries, requests)
+	_, err = os.Stat(dest)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUploadFilesFailure(t *testing.T) {
+	dir := t.TempDir()
+	var names []string
+	for i := range 8 {
+		name := fmt.Sprintf("file%d.patch", i)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+		names = append(names, name)
+	}
+	var mu sync.Mutex
+	var uploaded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := filepath.Base(r.URL.Path)
+		mu.Lock()
+		uploaded = append(uploaded, name)
+		mu.Unlock()
+		if name == "file0.patch" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, MaxUploadConcurrency: 1, Retries: -1}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	err := cred.uploadFiles(context.Background(), req, "home:testuser", "testpackage", dir, names)
+	assert.ErrorContains(t, err, "failed to upload file file0.patch")
+	// the failed upload cancels the ones which didn't start yet
+	assert.Equal(t, "file0.patch", uploaded[len(uploaded)-1])
+}
+
+func TestUploadFileRetry(t *testing.T) {
+	oldDelay := retryDelay
+	retryDelay = time.Millisecond
+	defer func() { retryDelay = oldDelay }()
+
+	file := filepath.Join(t.TempDir(), "foo.tar.gz")
+	assert.NoError(t, os.WriteFile(file, []byte("content"), 0644))
+	var attempts int
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "content", string(body))
+		if attempts < 3 {
+			w.WriteHeader(status)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, Retries: 3}
+	status = http.StatusServiceUnavailable
+	assert.NoError(t, cred.uploadFile(context.Background(), "home:testuser", "foo", "foo.tar.gz", file))
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	status = http.StatusForbidden
+	assert.Error(t, cred.uploadFile(context.Background(), "home:testuser", "foo", "foo.tar.gz", file))
+	assert.Equal(t, 1, attempts)
+
+	attempts = 0
+	status = http.StatusBadGateway
+	cred.Retries = -1
+	assert.Error(t, cred.uploadFile(context.Background(), "home:testuser", "foo", "foo.tar.gz", file))
+	assert.Equal(t, 1, attempts)
+
+	// without a setting the default number of retries is used
+	attempts = 0
+	cred.Retries = 0
+	assert.NoError(t, cred.uploadFile(context.Background(), "home:testuser", "foo", "foo.tar.gz", file))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestCommitDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.spec"), []byte("Name: testpackage\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.changes"), []byte("old entry\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "new.patch"), []byte("new\n"), 0644))
+	specMd5, err := fileMD5(filepath.Join(dir, "testpackage.spec"))
+	assert.NoError(t, err)
+	changesMd5, err := fileMD5(filepath.Join(dir, "testpackage.changes"))
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage":
+			fmt.Fprintf(w, `<directory name="testpackage" rev="1">
+  <entry name="testpackage.spec" md5="%s" size="1" mtime="1"/>
+  <entry name="testpackage.changes" md5="%s" size="1" mtime="1"/>
+  <entry name="old.tar.gz" md5="11111111111111111111111111111111" size="1" mtime="1"/>
+</directory>`, specMd5, changesMd5)
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/_meta"):
+			fmt.Fprint(w, `<package name="testpackage" project="home:testuser"><title/><description/></package>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, result, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:       "update",
+		Directory:     dir,
+		DryRun:        true,
+		ChangesAuthor: "Jane Packager",
+		ChangesEmail:  "jane@example.com",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Revision)
+	assert.Equal(t, []string{"new.patch"}, result.Added)
+	assert.Equal(t, []string{"testpackage.changes"}, result.Changed)
+	assert.Equal(t, []string{"old.tar.gz"}, result.Deleted)
+	assert.Contains(t, result.ChangesEntry, "- update")
+	assert.Contains(t, result.ChangesEntry, " - Jane Packager <jane@example.com>")
+	content, _ := os.ReadFile(filepath.Join(dir, "testpackage.changes"))
+	assert.Equal(t, "old entry\n", string(content))
+
+	_, _, err = cred.Commit(context.Background(), req, CommitCmd{
+		Message:      "update",
+		Directory:    dir,
+		DryRun:       true,
+		ChangesEmail: "Jane <jane@example.com>",
+	})
+	assert.ErrorContains(t, err, "invalid changes email address")
+}
+
+func TestVerifyUploads(t *testing.T) {
+	remote := &Directory{Entries: []Entry{
+		{Name: "foo.spec", Md5: "aaa"},
+		{Name: "foo.tar.gz", Md5: "bbb"},
+	}}
+	assert.Empty(t, verifyUploads(remote, []string{"foo.spec", "foo.tar.gz"}, map[string]string{"foo.spec": "aaa", "foo.tar.gz": "bbb"}))
+	warning := verifyUploads(remote, []string{"foo.tar.gz", "new.patch"}, map[string]string{"foo.tar.gz": "ccc", "new.patch": "ddd"})
+	assert.Contains(t, warning, "foo.tar.gz, new.patch")
+}
+
+func TestCommitOnlyFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.spec"), []byte("Name: testpackage\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fix.patch"), []byte("changed\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "new.patch"), []byte("new\n"), 0644))
+	sourcesDir := filepath.Join(dir, ".osc", "sources")
+	assert.NoError(t, os.MkdirAll(sourcesDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(sourcesDir, "testpackage.spec"), []byte("Name: old\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(sourcesDir, "fix.patch"), []byte("original\n"), 0644))
+
+	var mu sync.Mutex
+	var uploaded []string
+	var commitXML string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage":
+			fmt.Fprint(w, `<directory name="testpackage" rev="1">
+  <entry name="testpackage.spec" md5="00000000000000000000000000000000" size="1" mtime="1"/>
+  <entry name="fix.patch" md5="22222222222222222222222222222222" size="1" mtime="1"/>
+  <entry name="old.tar.gz" md5="11111111111111111111111111111111" size="1" mtime="1"/>
+</directory>`)
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/_meta"):
+			fmt.Fprint(w, `<package name="testpackage" project="home:testuser"><title/><description/></package>`)
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage/old.tar.gz":
+			fmt.Fprint(w, "tarball")
+		case r.Method == "PUT":
+			mu.Lock()
+			uploaded = append(uploaded, filepath.Base(r.URL.Path))
+			mu.Unlock()
+		case r.Method == "POST" && r.URL.Query().Get("cmd") == "commit":
+			body, _ := io.ReadAll(r.Body)
+			commitXML = string(body)
+			fmt.Fprint(w, `<revision rev="2"/>`)
+		case r.Method == "POST" && r.URL.Query().Get("cmd") == "diff":
+			assert.Equal(t, "2", r.URL.Query().Get("rev"))
+			fmt.Fprint(w, "+Name: testpackage\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, useInternalCommit: true}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}, Params: &mcp.CallToolParamsRaw{}}
+	_, result, err := cred.Commit(context.Background(), req, CommitCmd{
+		Message:             "update",
+		Directory:           dir,
+		SkipChangesCreation: true,
+		SkipVerify:          true,
+		IncludeDiff:         true,
+		OnlyFiles:           []string{"testpackage.spec"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Added)
+	assert.Equal(t, []string{"testpackage.spec"}, result.Changed)
+	assert.Empty(t, result.Deleted)
+	assert.Equal(t, []string{"testpackage.spec"}, uploaded)
+	assert.Contains(t, commitXML, `name="testpackage.spec"`)
+	assert.Contains(t, commitXML, `name="fix.patch" md5="22222222222222222222222222222222"`)
+	assert.Contains(t, commitXML, `name="old.tar.gz"`)
+	assert.NotContains(t, commitXML, "new.patch")
+	assert.Equal(t, "+Name: testpackage\n", result.Diff)
+
+	// only the committed files are refreshed in .osc/sources, the files which
+	// were left out keep the content of the server
+	for file, expected := range map[string]string{
+		filepath.Join(sourcesDir, "testpackage.spec"): "Name: testpackage\n",
+		filepath.Join(sourcesDir, "fix.patch"):        "original\n",
+		filepath.Join(sourcesDir, "old.tar.gz"):       "tarball",
+		filepath.Join(dir, "fix.patch"):               "changed\n",
+	} {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(content), file)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "old.tar.gz"))
+}
+
+func TestFindChangesFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"foo.spec", "foo.changes", "foo-doc.spec", "foo-doc.changes", "foo-docs.changes"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0644))
+	}
+	assert.Equal(t, filepath.Join(dir, "foo.changes"), findChangesFile(dir, "foo"))
+	assert.Equal(t, filepath.Join(dir, "foo-doc.changes"), findChangesFile(dir, "foo-doc"))
+
+	// a single spec file with another name than the bundle
+	dir = t.TempDir()
+	for _, name := range []string{"python-foo.spec", "python-foo.changes", "foo.changes"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0644))
+	}
+	assert.Equal(t, filepath.Join(dir, "python-foo.changes"), findChangesFile(dir, "foo-bar"))
+
+	// fuzzy match without an exact one
+	dir = t.TempDir()
+	for _, name := range []string{"foo.spec", "foo-doc.spec", "foo-1.changes"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0644))
+	}
+	assert.Equal(t, filepath.Join(dir, "foo-1.changes"), findChangesFile(dir, "foo-bar"))
+	assert.Empty(t, findChangesFile(t.TempDir(), "foo"))
+}
+
+func TestPrependChangesEntry(t *testing.T) {
+	changesFile := filepath.Join(t.TempDir(), "foo.changes")
+	assert.NoError(t, os.WriteFile(changesFile, []byte("\xef\xbb\xbf-----\r\nold entry\r\n"), 0644))
+	assert.NoError(t, prependChangesEntry(changesFile, "new entry\n"))
+	content, _ := os.ReadFile(changesFile)
+	assert.Equal(t, "new entry\n-----\nold entry\n", string(content))
+
+	newFile := filepath.Join(t.TempDir(), "new.changes")
+	assert.NoError(t, prependChangesEntry(newFile, "new entry\n"))
+	content, _ = os.ReadFile(newFile)
+	assert.Equal(t, "new entry\n", string(content))
+
+	assert.NoError(t, os.WriteFile(changesFile, []byte("binary\x00content"), 0644))
+	assert.ErrorContains(t, prependChangesEntry(changesFile, "new entry\n"), "not a text file")
+}
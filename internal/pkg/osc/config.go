@@ -0,0 +1,59 @@
+package osc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetConfigParam struct{}
+
+type GetConfigResult struct {
+	ApiAddr              string   `json:"api_address"`
+	User                 string   `json:"user"`
+	Auth                 string   `json:"auth" jsonschema:"token or password"`
+	EMail                string   `json:"email,omitempty"`
+	WorkDir              string   `json:"workdir"`
+	BuildRootInWorkdir   bool     `json:"build_root_in_workdir"`
+	MaxLogLines          int      `json:"max_log_lines"`
+	Concurrency          int      `json:"concurrency"`
+	MaxUploadConcurrency int      `json:"max_upload_concurrency"`
+	EnabledTools         []string `json:"enabled_tools"`
+}
+
+func (cred *OSCCredentials) GetConfig(ctx context.Context, req *mcp.CallToolRequest, params GetConfigParam) (*mcp.CallToolResult, *GetConfigResult, error) {
+	slog.Debug("mcp tool call: GetConfig")
+	maxLogLines := cred.MaxLogLines
+	if maxLogLines <= 0 {
+		maxLogLines = maxLines
+	}
+	concurrency := cred.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	maxUploadConcurrency := cred.MaxUploadConcurrency
+	if maxUploadConcurrency <= 0 {
+		maxUploadConcurrency = defaultConcurrency
+	}
+	auth := "password"
+	if cred.Token != "" {
+		auth = "token"
+	}
+	enabledTools := cred.EnabledTools
+	if enabledTools == nil {
+		enabledTools = []string{}
+	}
+	return nil, &GetConfigResult{
+		ApiAddr:              cred.GetAPiAddr(),
+		User:                 cred.Name,
+		Auth:                 auth,
+		EMail:                cred.EMail,
+		WorkDir:              cred.TempDir,
+		BuildRootInWorkdir:   cred.buildRootInWorkdir,
+		MaxLogLines:          maxLogLines,
+		Concurrency:          concurrency,
+		MaxUploadConcurrency: maxUploadConcurrency,
+		EnabledTools:         enabledTools,
+	}, nil
+}
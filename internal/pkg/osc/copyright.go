@@ -0,0 +1,154 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var copyrightRegexp = regexp.MustCompile(`Copyright\s*(\([cC]\))?[\s\d,-]*(\S*)`)
+
+// copyrightHolder returns the first word of the holder of a copyright line,
+// so that e.g. "SUSE LLC" and "SUSE LLC and contributors" are the same.
+func copyrightHolder(line string) string {
+	match := copyrightRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[2])
+}
+
+// splitHeader splits the leading block of comment and empty lines of a file
+// from the rest. Only a block which contains a copyright line is treated as
+// header, in which case the header ends with its last line consisting of a
+// single '#'.
+func splitHeader(content string) (header []string, rest string) {
+	lines := strings.SplitAfter(content, "\n")
+	end := 0
+	hasCopyright := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if strings.Contains(trimmed, "Copyright") {
+			hasCopyright = true
+		}
+		if trimmed == "#" && hasCopyright {
+			end = i + 1
+		}
+	}
+	if !hasCopyright {
+		return nil, content
+	}
+	if end == 0 {
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), "#") {
+			end++
+		}
+	}
+	return lines[:end], strings.Join(lines[end:], "")
+}
+
+// applyCopyrightHeader inserts the header at the top of content or replaces
+// an existing one. Copyright lines of other holders in the existing header
+// are kept below the copyright line of the new header.
+func applyCopyrightHeader(content, header string) (string, bool) {
+	oldHeader, rest := splitHeader(content)
+	newLines := strings.SplitAfter(strings.TrimRight(header, "\n")+"\n", "\n")
+	copyrightIdx := -1
+	for i, line := range newLines {
+		if strings.Contains(line, "Copyright") {
+			copyrightIdx = i
+			break
+		}
+	}
+	var kept []string
+	if copyrightIdx >= 0 {
+		own := copyrightHolder(newLines[copyrightIdx])
+		for _, line := range oldHeader {
+			if strings.Contains(line, "Copyright") && copyrightHolder(line) != own {
+				kept = append(kept, line)
+			}
+		}
+	}
+	var result strings.Builder
+	for i, line := range newLines {
+		if line == "" {
+			continue
+		}
+		result.WriteString(line)
+		if i == copyrightIdx {
+			for _, line := range kept {
+				result.WriteString(line)
+			}
+		}
+	}
+	result.WriteString("\n")
+	result.WriteString(strings.TrimLeft(rest, "\n"))
+	return result.String(), oldHeader != nil
+}
+
+type ApplyCopyrightHeaderParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle"`
+	FileName    string `json:"file_name,omitempty" jsonschema:"File in the local bundle to apply the header to. Defaults to the spec file of the bundle."`
+}
+
+type ApplyCopyrightHeaderResult struct {
+	Path     string `json:"path"`
+	Replaced bool   `json:"replaced" jsonschema:"True if an existing header was refreshed, false if the header was inserted."`
+	Content  string `json:"content"`
+}
+
+func (cred *OSCCredentials) ApplyCopyrightHeader(ctx context.Context, req *mcp.CallToolRequest, params ApplyCopyrightHeaderParam) (*mcp.CallToolResult, *ApplyCopyrightHeaderResult, error) {
+	slog.Debug("mcp tool call: ApplyCopyrightHeader", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	fileName := params.FileName
+	if fileName == "" {
+		fileName = params.PackageName + ".spec"
+	}
+	if filepath.Base(fileName) != fileName {
+		return nil, nil, fmt.Errorf("invalid file name: %s", fileName)
+	}
+	defaults, err := ReadDefaults()
+	if err != nil {
+		return nil, nil, err
+	}
+	if defaults.CopyrightHeader == "" {
+		return nil, nil, fmt.Errorf("no copyright header configured in defaults.yaml")
+	}
+
+	path := filepath.Join(cred.TempDir, params.ProjectName, params.PackageName, fileName)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	packageName := params.PackageName
+	if strings.HasSuffix(fileName, ".spec") {
+		packageName = strings.TrimSuffix(fileName, ".spec")
+	}
+	header := strings.ReplaceAll(defaults.CopyrightHeader, "__PACKAGE_NAME__", packageName)
+	header = strings.ReplaceAll(header, "__YEAR__", fmt.Sprintf("%d", time.Now().Year()))
+
+	newContent, replaced := applyCopyrightHeader(string(content), header)
+	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil, &ApplyCopyrightHeaderResult{
+		Path:     path,
+		Replaced: replaced,
+		Content:  newContent,
+	}, nil
+}
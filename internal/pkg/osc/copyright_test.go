@@ -0,0 +1,68 @@
+package osc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testHeader = `#
+# spec file for package foo
+#
+# Copyright (c) 2026 SUSE LLC and contributors
+#
+# All modifications and additions to the file contributed by third parties
+# remain the property of their copyright owners.
+
+# Please submit bugfixes or comments via https://bugs.opensuse.org/
+#
+`
+
+func TestApplyCopyrightHeader(t *testing.T) {
+	body := "Name:           foo\nVersion:        1.0\n"
+
+	content, replaced := applyCopyrightHeader(body, testHeader)
+	assert.False(t, replaced)
+	assert.Equal(t, testHeader+"\n"+body, content)
+
+	// applying it again doesn't duplicate the header
+	again, replaced := applyCopyrightHeader(content, testHeader)
+	assert.True(t, replaced)
+	assert.Equal(t, content, again)
+
+	old := `#
+# spec file for package foo
+#
+# Copyright (c) 2019 SUSE LLC
+# Copyright (c) 2018 Jane Doe <jane@example.org>
+#
+# Please submit bugfixes or comments via https://bugs.opensuse.org/
+#
+
+
+# needssslcertforbuild
+Name:           foo
+`
+	content, replaced = applyCopyrightHeader(old, testHeader)
+	assert.True(t, replaced)
+	assert.Equal(t, `#
+# spec file for package foo
+#
+# Copyright (c) 2026 SUSE LLC and contributors
+# Copyright (c) 2018 Jane Doe <jane@example.org>
+#
+# All modifications and additions to the file contributed by third parties
+# remain the property of their copyright owners.
+
+# Please submit bugfixes or comments via https://bugs.opensuse.org/
+#
+
+# needssslcertforbuild
+Name:           foo
+`, content)
+
+	// comments without a copyright are not a header
+	content, replaced = applyCopyrightHeader("# norootforbuild\n"+body, testHeader)
+	assert.False(t, replaced)
+	assert.Equal(t, testHeader+"\n# norootforbuild\n"+body, content)
+}
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/beevik/etree"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -16,17 +15,25 @@ type DeleteProjectParam struct {
 	ProjectName string `json:"project_name,omitempty" jsonschema:"The project to be deleted. Defaults to home:$USERNAME:$SESSIONID if not provided."`
 	Force       bool   `json:"force,omitempty" jsonschema:"Set to true to delete the project even if other projects link to it."`
 	Comment     string `json:"comment,omitempty" jsonschema:"A comment explaining the reason for the deletion."`
+	Confirm     bool   `json:"confirm" jsonschema:"Must be true to delete the project. Ask the user before deleting a project."`
 }
 
 type DeleteProjectResult struct {
 	Message string `json:"message"`
 }
 
-func (cred OSCCredentials) DeleteProject(ctx context.Context, req *mcp.CallToolRequest, params DeleteProjectParam) (*mcp.CallToolResult, DeleteProjectResult, error) {
-	slog.Debug("mcp tool call: DeleteProject", "session", req.Session.ID(), "params", params)
+func (cred *OSCCredentials) DeleteProject(ctx context.Context, req *mcp.CallToolRequest, params DeleteProjectParam) (*mcp.CallToolResult, DeleteProjectResult, error) {
+	slog.Debug("mcp tool call: DeleteProject", "session", sessionId(req), "params", params)
 	projectName := params.ProjectName
 	if projectName == "" {
-		projectName = fmt.Sprintf("home:%s:%s", cred.Name, req.Session.ID())
+		session := sessionId(req)
+		if session == "" {
+			return nil, DeleteProjectResult{}, fmt.Errorf("project name cannot be empty without a session")
+		}
+		projectName = fmt.Sprintf("home:%s:%s", cred.Name, session)
+	}
+	if !params.Confirm {
+		return nil, DeleteProjectResult{}, fmt.Errorf("deleting project %s needs confirm set to true", projectName)
 	}
 
 	apiURL, err := url.Parse(fmt.Sprintf("%s/source/%s", cred.GetAPiAddr(), projectName))
@@ -43,17 +50,13 @@ func (cred OSCCredentials) DeleteProject(ctx context.Context, req *mcp.CallToolR
 	}
 	apiURL.RawQuery = q.Encode()
 
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", apiURL.String(), nil)
+	httpReq, err := cred.buildRequest(ctx, "DELETE", apiURL.String(), nil)
 	if err != nil {
 		return nil, DeleteProjectResult{}, fmt.Errorf("failed to create request: %w", err)
 	}
-
-	httpReq.Header.Set("User-Agent", "osc-mcp")
-	httpReq.SetBasicAuth(cred.Name, cred.Passwd)
 	httpReq.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := cred.do(httpReq)
 	if err != nil {
 		return nil, DeleteProjectResult{}, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -63,20 +66,18 @@ func (cred OSCCredentials) DeleteProject(ctx context.Context, req *mcp.CallToolR
 	if err != nil {
 		return nil, DeleteProjectResult{}, fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, DeleteProjectResult{}, fmt.Errorf("api request failed with status: %s\nbody:\n%s", resp.Status, string(body))
-	}
-
-	doc := etree.NewDocument()
-	if err := doc.ReadFromBytes(body); err != nil {
-		return nil, DeleteProjectResult{}, fmt.Errorf("failed to parse response xml: %w", err)
+	code, summary := statusSummary(body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return nil, DeleteProjectResult{}, fmt.Errorf("no permission to delete %s: %s", projectName, summary)
+	case http.StatusNotFound:
+		return nil, DeleteProjectResult{}, fmt.Errorf("%w: %s", ErrBundleOrProjectNotFound, summary)
+	default:
+		return nil, DeleteProjectResult{}, fmt.Errorf("api request failed with status: %s: %s %s", resp.Status, code, summary)
 	}
 
-	status := doc.SelectElement("status")
-	summary := status.SelectElement("summary")
-
 	return nil, DeleteProjectResult{
-		Message: fmt.Sprintf("Project '%s' deleted successfully: %s", projectName, summary.Text()),
+		Message: fmt.Sprintf("Project '%s' deleted successfully: %s", projectName, summary),
 	}, nil
 }
@@ -0,0 +1,48 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteProject(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		switch r.URL.Path {
+		case "/source/home:testuser:scratch":
+			assert.Equal(t, "1", r.URL.Query().Get("force"))
+			assert.Equal(t, "cleanup", r.URL.Query().Get("comment"))
+			deleted = true
+			fmt.Fprint(w, `<status code="ok"><summary>Ok</summary></status>`)
+		case "/source/openSUSE:Factory":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<status code="delete_project_no_permission"><summary>No permission to delete project openSUSE:Factory</summary></status>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, _, err := cred.DeleteProject(context.Background(), nil, DeleteProjectParam{ProjectName: "home:testuser:scratch"})
+	assert.ErrorContains(t, err, "confirm")
+	assert.False(t, deleted)
+
+	_, result, err := cred.DeleteProject(context.Background(), nil, DeleteProjectParam{ProjectName: "home:testuser:scratch", Force: true, Comment: "cleanup", Confirm: true})
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, "Project 'home:testuser:scratch' deleted successfully: Ok", result.Message)
+
+	_, _, err = cred.DeleteProject(context.Background(), nil, DeleteProjectParam{ProjectName: "openSUSE:Factory", Confirm: true})
+	assert.ErrorContains(t, err, "No permission to delete project")
+
+	_, _, err = cred.DeleteProject(context.Background(), nil, DeleteProjectParam{Confirm: true})
+	assert.ErrorContains(t, err, "without a session")
+}
@@ -0,0 +1,68 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type WhatDependsOnParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle whose dependents are searched"`
+	Repository  string `json:"repository" jsonschema:"Repository of the project, e.g. openSUSE_Tumbleweed"`
+	Arch        string `json:"arch" jsonschema:"Architecture of the repository, e.g. x86_64"`
+}
+
+type WhatDependsOnResult struct {
+	PackageName string   `json:"package_name"`
+	Dependents  []string `json:"dependents" jsonschema:"Bundles of the repository which need the bundle to build"`
+}
+
+// reverseDeps returns the sorted names of the packages which depend on
+// packageName. With the revpkgnames view the dependents are the pkgdeps of
+// the package itself, otherwise they are the packages with a dep on it.
+func reverseDeps(depInfo *BuildDepInfo, packageName string) []string {
+	dependents := []string{}
+	add := func(name string) {
+		if name != packageName && !slices.Contains(dependents, name) {
+			dependents = append(dependents, name)
+		}
+	}
+	for _, p := range depInfo.Packages {
+		if p.Name == packageName {
+			for _, name := range p.PkgDeps {
+				add(name)
+			}
+			continue
+		}
+		for _, d := range p.Deps {
+			if d.Name == packageName {
+				add(p.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(dependents)
+	return dependents
+}
+
+func (cred *OSCCredentials) WhatDependsOn(ctx context.Context, req *mcp.CallToolRequest, params WhatDependsOnParam) (*mcp.CallToolResult, *WhatDependsOnResult, error) {
+	slog.Debug("mcp tool call: WhatDependsOn", "params", params)
+	if params.ProjectName == "" || params.PackageName == "" {
+		return nil, nil, fmt.Errorf("project and package name cannot be empty")
+	}
+	if params.Repository == "" || params.Arch == "" {
+		return nil, nil, fmt.Errorf("repository and arch cannot be empty")
+	}
+	depInfo, err := cred.getBuildDepInfoView(ctx, params.ProjectName, params.Repository, params.Arch, "revpkgnames")
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &WhatDependsOnResult{
+		PackageName: params.PackageName,
+		Dependents:  reverseDeps(depInfo, params.PackageName),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package osc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseDeps(t *testing.T) {
+	var depInfo BuildDepInfo
+	assert.NoError(t, xml.Unmarshal([]byte(`<builddepinfo>
+  <package name="libfoo"><dep name="glibc" state="ok"/></package>
+  <package name="bar"><dep name="libfoo" state="ok"/><dep name="glibc" state="ok"/></package>
+  <package name="baz"><dep name="libfoo" state="missing"/></package>
+  <package name="qux"><dep name="glibc" state="ok"/></package>
+</builddepinfo>`), &depInfo))
+	assert.Equal(t, []string{"bar", "baz"}, reverseDeps(&depInfo, "libfoo"))
+	assert.Equal(t, []string{}, reverseDeps(&depInfo, "qux"))
+}
+
+func TestWhatDependsOn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/build/openSUSE:Factory/standard/x86_64/_builddepinfo", r.URL.Path)
+		assert.Equal(t, "revpkgnames", r.URL.Query().Get("view"))
+		fmt.Fprint(w, `<builddepinfo>
+  <package name="libfoo">
+    <source>libfoo</source>
+    <pkgdep>python-foo</pkgdep>
+    <pkgdep>bar</pkgdep>
+  </package>
+  <package name="bar"><source>bar</source></package>
+</builddepinfo>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.WhatDependsOn(context.Background(), nil, WhatDependsOnParam{ProjectName: "openSUSE:Factory", PackageName: "libfoo", Repository: "standard", Arch: "x86_64"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bar", "python-foo"}, result.Dependents)
+}
@@ -0,0 +1,95 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type SourceDiffParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Revision    string `json:"rev,omitempty" jsonschema:"Revision to compare. Defaults to the latest revision."`
+	OldRevision string `json:"orev,omitempty" jsonschema:"Revision to compare against. Defaults to the revision before rev."`
+}
+
+type SourceDiffResult struct {
+	Diff     string `json:"diff"`
+	Expanded bool   `json:"expanded"`
+	Note     string `json:"note,omitempty"`
+}
+
+func (cred *OSCCredentials) getSourceDiff(ctx context.Context, projectName, packageName, orev, rev string, expand bool) (string, int, error) {
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "diff")
+	if orev != "" {
+		queryParams.Set("orev", orev)
+	}
+	if rev != "" {
+		queryParams.Set("rev", rev)
+	}
+	if expand {
+		queryParams.Set("expand", "1")
+	}
+	return cred.postSourceDiff(ctx, projectName, packageName, queryParams)
+}
+
+// postSourceDiff runs the diff command of a package with the given parameters
+// and returns the diff together with the status code of the response.
+func (cred *OSCCredentials) postSourceDiff(ctx context.Context, projectName, packageName string, queryParams url.Values) (string, int, error) {
+	diffURL := fmt.Sprintf("%s/source/%s/%s?%s", cred.GetAPiAddr(), projectName, packageName, queryParams.Encode())
+	slog.Debug("Getting source diff from OBS", "url", diffURL)
+
+	oscReq, err := cred.buildRequest(ctx, "POST", diffURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	resp, err := cred.do(oscReq)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", resp.StatusCode, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return string(body), resp.StatusCode, fmt.Errorf("failed to get source diff: status %s, body: %s", resp.Status, string(body))
+	}
+	return string(body), resp.StatusCode, nil
+}
+
+func (cred *OSCCredentials) SourceDiff(ctx context.Context, req *mcp.CallToolRequest, params SourceDiffParam) (*mcp.CallToolResult, *SourceDiffResult, error) {
+	slog.Debug("mcp tool call: SourceDiff", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+
+	diff, statusCode, err := cred.getSourceDiff(ctx, params.ProjectName, params.PackageName, params.OldRevision, params.Revision, true)
+	if err == nil {
+		return nil, &SourceDiffResult{Diff: diff, Expanded: true}, nil
+	}
+	if statusCode == 0 || statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return nil, nil, err
+	}
+	// the link could not be expanded, so try again with the unexpanded sources
+	slog.Warn("expanded source diff failed, retrying without expansion", "error", err)
+	unexpanded, _, retryErr := cred.getSourceDiff(ctx, params.ProjectName, params.PackageName, params.OldRevision, params.Revision, false)
+	if retryErr != nil {
+		return nil, nil, retryErr
+	}
+	return nil, &SourceDiffResult{
+		Diff:     unexpanded,
+		Expanded: false,
+		Note:     fmt.Sprintf("The link could not be expanded, the diff is for the unexpanded sources: %s", strings.TrimSpace(diff)),
+	}, nil
+}
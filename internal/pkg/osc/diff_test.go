@@ -0,0 +1,49 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "diff", r.URL.Query().Get("cmd"))
+		switch r.URL.Path {
+		case "/source/home:testuser/foo":
+			assert.Equal(t, "1", r.URL.Query().Get("expand"))
+			assert.Equal(t, "3", r.URL.Query().Get("orev"))
+			assert.Equal(t, "4", r.URL.Query().Get("rev"))
+			fmt.Fprint(w, "--- foo.spec\n+++ foo.spec\n-Version: 1.0\n+Version: 1.1\n")
+		case "/source/home:testuser:branches/foo":
+			if r.URL.Query().Get("expand") == "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `<status code="expand_error"><summary>conflict in file foo.spec</summary></status>`)
+				return
+			}
+			fmt.Fprint(w, "--- _link\n+++ _link\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.SourceDiff(context.Background(), &mcp.CallToolRequest{}, SourceDiffParam{ProjectName: "home:testuser", PackageName: "foo", Revision: "4", OldRevision: "3"})
+	assert.NoError(t, err)
+	assert.True(t, result.Expanded)
+	assert.Contains(t, result.Diff, "+Version: 1.1")
+
+	_, result, err = cred.SourceDiff(context.Background(), &mcp.CallToolRequest{}, SourceDiffParam{ProjectName: "home:testuser:branches", PackageName: "foo"})
+	assert.NoError(t, err)
+	assert.False(t, result.Expanded)
+	assert.Equal(t, "--- _link\n+++ _link\n", result.Diff)
+	assert.Contains(t, result.Note, "conflict in file foo.spec")
+}
@@ -0,0 +1,146 @@
+package osc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultExportLimit = 100
+
+type ExportProjectParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	Filter      string `json:"filter,omitempty" jsonschema:"Optional regexp to select the exported packages"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximal number of exported packages. Defaults to 100."`
+	Format      string `json:"format,omitempty" jsonschema:"Format of the written manifest, yaml or json. Defaults to yaml."`
+}
+
+type PackageExport struct {
+	Name string `json:"name" yaml:"name"`
+	Meta string `json:"meta" yaml:"meta"`
+}
+
+type ProjectExport struct {
+	ProjectName string          `json:"project_name" yaml:"project_name"`
+	Exported    string          `json:"exported" yaml:"exported"`
+	Api         string          `json:"api" yaml:"api"`
+	Meta        string          `json:"meta" yaml:"meta"`
+	Packages    []PackageExport `json:"packages" yaml:"packages"`
+	NumPackages int             `json:"num_packages" yaml:"num_packages"`
+	Truncated   bool            `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+}
+
+type ExportProjectResult struct {
+	File     string         `json:"file" jsonschema:"File the manifest was written to"`
+	Manifest *ProjectExport `json:"manifest"`
+}
+
+// exportProject collects the meta of a project and of its packages. The metas
+// of the packages are fetched in parallel.
+func (cred *OSCCredentials) exportProject(ctx context.Context, projectName, filter string, limit int) (*ProjectExport, error) {
+	var re *regexp.Regexp
+	if filter != "" {
+		var err error
+		if re, err = regexp.Compile(filter); err != nil {
+			return nil, fmt.Errorf("invalid filter regexp: %w", err)
+		}
+	}
+	projectDoc, err := cred.getMetaDoc(ctx, projectName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meta of project %s: %w", projectName, err)
+	}
+	projectMeta, err := projectDoc.WriteToString()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write project meta: %w", err)
+	}
+	names, err := cred.getProjectPackageNames(ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages for project %s: %w", projectName, err)
+	}
+
+	export := &ProjectExport{
+		ProjectName: projectName,
+		Exported:    time.Now().Format(time.RFC3339),
+		Api:         cred.GetAPiAddr(),
+		Meta:        projectMeta,
+		Packages:    []PackageExport{},
+	}
+	for _, name := range names {
+		if re != nil && !re.MatchString(name) {
+			continue
+		}
+		if len(export.Packages) >= limit {
+			export.Truncated = true
+			break
+		}
+		export.Packages = append(export.Packages, PackageExport{Name: name})
+	}
+	export.NumPackages = len(export.Packages)
+
+	tasks := make([]func() error, len(export.Packages))
+	for i := range export.Packages {
+		pkg := &export.Packages[i]
+		tasks[i] = func() error {
+			doc, err := cred.getMetaDoc(ctx, projectName, pkg.Name)
+			if err != nil {
+				return fmt.Errorf("failed to get meta of package %s: %w", pkg.Name, err)
+			}
+			pkg.Meta, err = doc.WriteToString()
+			return err
+		}
+	}
+	for _, err := range runParallel(cred.Concurrency, tasks...) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return export, nil
+}
+
+func (cred *OSCCredentials) ExportProject(ctx context.Context, req *mcp.CallToolRequest, params ExportProjectParam) (*mcp.CallToolResult, *ExportProjectResult, error) {
+	slog.Debug("mcp tool call: ExportProject", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultExportLimit
+	}
+	format := params.Format
+	if format == "" {
+		format = "yaml"
+	}
+	if format != "yaml" && format != "json" {
+		return nil, nil, fmt.Errorf("format must be yaml or json, got '%s'", format)
+	}
+
+	export, err := cred.exportProject(ctx, params.ProjectName, params.Filter, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	var data []byte
+	if format == "json" {
+		data, err = json.MarshalIndent(export, "", "  ")
+	} else {
+		data, err = yaml.Marshal(export)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.MkdirAll(cred.TempDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create workdir: %w", err)
+	}
+	file := filepath.Join(cred.TempDir, fmt.Sprintf("%s.manifest.%s", params.ProjectName, format))
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil, &ExportProjectResult{File: file, Manifest: export}, nil
+}
@@ -0,0 +1,52 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/_meta":
+			fmt.Fprint(w, `<project name="home:testuser"><title>Test</title><description/></project>`)
+		case "/source/home:testuser":
+			fmt.Fprint(w, `<directory><entry name="bar"/><entry name="foo"/><entry name="foo-doc"/></directory>`)
+		case "/source/home:testuser/foo/_meta", "/source/home:testuser/foo-doc/_meta":
+			name := r.URL.Path[len("/source/home:testuser/") : len(r.URL.Path)-len("/_meta")]
+			fmt.Fprintf(w, `<package name="%s" project="home:testuser"><title/><description/></package>`, name)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, TempDir: t.TempDir()}
+	_, result, err := cred.ExportProject(context.Background(), nil, ExportProjectParam{ProjectName: "home:testuser", Filter: "^foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, `<project name="home:testuser"><title>Test</title><description/></project>`, result.Manifest.Meta)
+	assert.Equal(t, []PackageExport{
+		{Name: "foo", Meta: `<package name="foo" project="home:testuser"><title/><description/></package>`},
+		{Name: "foo-doc", Meta: `<package name="foo-doc" project="home:testuser"><title/><description/></package>`},
+	}, result.Manifest.Packages)
+	assert.False(t, result.Manifest.Truncated)
+
+	data, err := os.ReadFile(result.File)
+	assert.NoError(t, err)
+	var manifest ProjectExport
+	assert.NoError(t, yaml.Unmarshal(data, &manifest))
+	assert.Equal(t, *result.Manifest, manifest)
+
+	_, result, err = cred.ExportProject(context.Background(), nil, ExportProjectParam{ProjectName: "home:testuser", Filter: "^foo", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, result.Manifest.Packages, 1)
+	assert.True(t, result.Manifest.Truncated)
+}
@@ -0,0 +1,124 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListMyGroupsParam struct {
+	User string `json:"user,omitempty" jsonschema:"User to list the group memberships for. If not provided, the configured user is used."`
+}
+
+type ListMyGroupsResult struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+}
+
+type ListGroupMembersParam struct {
+	Group string `json:"group" jsonschema:"Name of the group"`
+}
+
+type ListGroupMembersResult struct {
+	Group       string   `json:"group"`
+	Title       string   `json:"title,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+	Members     []string `json:"members"`
+}
+
+func (cred *OSCCredentials) getGroupXML(ctx context.Context, path string) (*etree.Document, error) {
+	resp, err := cred.apiGetRequest(ctx, path, map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("group not found")
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return doc, nil
+}
+
+func (cred *OSCCredentials) ListMyGroups(ctx context.Context, req *mcp.CallToolRequest, params ListMyGroupsParam) (*mcp.CallToolResult, *ListMyGroupsResult, error) {
+	slog.Debug("mcp tool call: ListMyGroups", "params", params)
+	user := params.User
+	if user == "" {
+		user = cred.Name
+	}
+	if user == "" {
+		return nil, nil, fmt.Errorf("user must be specified")
+	}
+
+	doc, err := cred.getGroupXML(ctx, "group?login="+url.QueryEscape(user))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list groups of %s: %w", user, err)
+	}
+	dirElement := doc.SelectElement("directory")
+	if dirElement == nil {
+		return nil, nil, fmt.Errorf("directory not found in response")
+	}
+
+	result := &ListMyGroupsResult{
+		User:   user,
+		Groups: []string{},
+	}
+	for _, entry := range dirElement.SelectElements("entry") {
+		if name := entry.SelectAttrValue("name", ""); name != "" {
+			result.Groups = append(result.Groups, name)
+		}
+	}
+	return nil, result, nil
+}
+
+func (cred *OSCCredentials) ListGroupMembers(ctx context.Context, req *mcp.CallToolRequest, params ListGroupMembersParam) (*mcp.CallToolResult, *ListGroupMembersResult, error) {
+	slog.Debug("mcp tool call: ListGroupMembers", "params", params)
+	if params.Group == "" {
+		return nil, nil, fmt.Errorf("group name cannot be empty")
+	}
+
+	doc, err := cred.getGroupXML(ctx, "group/"+url.PathEscape(params.Group))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get group %s: %w", params.Group, err)
+	}
+	groupElement := doc.SelectElement("group")
+	if groupElement == nil {
+		return nil, nil, fmt.Errorf("group not found in response, name was: %s", params.Group)
+	}
+
+	result := &ListGroupMembersResult{
+		Group:   params.Group,
+		Members: []string{},
+	}
+	if title := groupElement.SelectElement("title"); title != nil {
+		result.Title = title.Text()
+	}
+	if email := groupElement.SelectElement("email"); email != nil {
+		result.Email = email.Text()
+	}
+	for _, maintainer := range groupElement.SelectElements("maintainer") {
+		if userid := maintainer.SelectAttrValue("userid", ""); userid != "" {
+			result.Maintainers = append(result.Maintainers, userid)
+		}
+	}
+	if persons := groupElement.SelectElement("person"); persons != nil {
+		for _, person := range persons.SelectElements("person") {
+			if userid := person.SelectAttrValue("userid", ""); userid != "" {
+				result.Members = append(result.Members, userid)
+			}
+		}
+	}
+	return nil, result, nil
+}
@@ -0,0 +1,185 @@
+package osc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type SourceRevision struct {
+	Rev       string `xml:"rev,attr" json:"rev"`
+	VRev      string `xml:"vrev,attr" json:"vrev,omitempty"`
+	SrcMd5    string `xml:"srcmd5" json:"srcmd5"`
+	Version   string `xml:"version" json:"version,omitempty"`
+	Time      int64  `xml:"time" json:"time"`
+	User      string `xml:"user" json:"user"`
+	Comment   string `xml:"comment" json:"comment,omitempty"`
+	RequestID string `xml:"requestid" json:"request_id,omitempty"`
+}
+
+type revisionList struct {
+	XMLName   xml.Name         `xml:"revisionlist"`
+	Revisions []SourceRevision `xml:"revision"`
+}
+
+// getPackageHistory returns the revisions of a package, oldest first.
+func (cred *OSCCredentials) getPackageHistory(ctx context.Context, projectName, packageName string) ([]SourceRevision, error) {
+	resp, err := cred.apiGetRequest(ctx, fmt.Sprintf("source/%s/%s/_history", projectName, packageName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("package %s/%s not found", projectName, packageName)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+
+	var history revisionList
+	if err := xml.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode history: %w", err)
+	}
+	if history.Revisions == nil {
+		history.Revisions = []SourceRevision{}
+	}
+	return history.Revisions, nil
+}
+
+type SummarizeHistoryParam struct {
+	ProjectName  string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName  string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	FromRevision int    `json:"from_rev,omitempty" jsonschema:"First revision to include. Defaults to the first revision."`
+	ToRevision   int    `json:"to_rev,omitempty" jsonschema:"Last revision to include. Defaults to the latest revision."`
+}
+
+type SummarizeHistoryResult struct {
+	Revisions    []string `json:"revisions"`
+	Summary      string   `json:"summary"`
+	ChangesEntry string   `json:"changes_entry"`
+}
+
+// summarizeComments creates a bullet list of the given revision comments.
+// Empty and duplicate lines are dropped, existing bullets are normalized.
+func summarizeComments(revisions []SourceRevision) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for _, rev := range revisions {
+		for _, line := range strings.Split(rev.Comment, "\n") {
+			line = strings.TrimSpace(line)
+			line = strings.TrimSpace(strings.TrimLeft(line, "-*"))
+			if line == "" || seen[line] {
+				continue
+			}
+			seen[line] = true
+			lines = append(lines, "- "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (cred *OSCCredentials) SummarizeHistory(ctx context.Context, req *mcp.CallToolRequest, params SummarizeHistoryParam) (*mcp.CallToolResult, *SummarizeHistoryResult, error) {
+	slog.Debug("mcp tool call: SummarizeHistory", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	if params.ToRevision > 0 && params.FromRevision > params.ToRevision {
+		return nil, nil, fmt.Errorf("from_rev %d is bigger than to_rev %d", params.FromRevision, params.ToRevision)
+	}
+
+	history, err := cred.getPackageHistory(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get history of %s/%s: %w", params.ProjectName, params.PackageName, err)
+	}
+
+	result := &SummarizeHistoryResult{
+		Revisions: []string{},
+	}
+	var selected []SourceRevision
+	for _, rev := range history {
+		nr, err := strconv.Atoi(rev.Rev)
+		if err != nil {
+			continue
+		}
+		if nr < params.FromRevision || (params.ToRevision > 0 && nr > params.ToRevision) {
+			continue
+		}
+		selected = append(selected, rev)
+		result.Revisions = append(result.Revisions, rev.Rev)
+	}
+	if len(selected) == 0 {
+		return nil, nil, fmt.Errorf("no revisions found in the given range")
+	}
+	result.Summary = summarizeComments(selected)
+	if result.Summary != "" {
+		result.ChangesEntry = createChangesEntry(result.Summary, cred.Name+"-mcpbot", cred.EMail)
+	}
+	return nil, result, nil
+}
+
+type GetPackageHistoryParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Statistics  bool   `json:"statistics,omitempty" jsonschema:"Also return the number of commits per author and the time span of the history."`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Only return the newest revisions up to this number. The statistics still cover the whole history."`
+}
+
+type GetPackageHistoryResult struct {
+	Revisions []SourceRevision `json:"revisions"`
+	Total     int              `json:"total" jsonschema:"Number of all revisions of the bundle"`
+	Authors   map[string]int   `json:"authors,omitempty"`
+	Since     string           `json:"since,omitempty"`
+	Until     string           `json:"until,omitempty"`
+}
+
+func (cred *OSCCredentials) GetPackageHistory(ctx context.Context, req *mcp.CallToolRequest, params GetPackageHistoryParam) (*mcp.CallToolResult, *GetPackageHistoryResult, error) {
+	slog.Debug("mcp tool call: GetPackageHistory", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	if params.Limit < 0 {
+		return nil, nil, fmt.Errorf("limit cannot be negative")
+	}
+
+	history, err := cred.getPackageHistory(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get history of %s/%s: %w", params.ProjectName, params.PackageName, err)
+	}
+	result := &GetPackageHistoryResult{
+		Revisions: history,
+		Total:     len(history),
+	}
+	// the history is sorted oldest first, so the newest are at the end
+	if params.Limit > 0 && params.Limit < len(history) {
+		result.Revisions = history[len(history)-params.Limit:]
+	}
+	if params.Statistics && len(history) > 0 {
+		result.Authors = make(map[string]int)
+		first, last := history[0].Time, history[0].Time
+		for _, rev := range history {
+			result.Authors[rev.User]++
+			if rev.Time < first {
+				first = rev.Time
+			}
+			if rev.Time > last {
+				last = rev.Time
+			}
+		}
+		result.Since = time.Unix(first, 0).UTC().Format(time.RFC3339)
+		result.Until = time.Unix(last, 0).UTC().Format(time.RFC3339)
+	}
+	return nil, result, nil
+}
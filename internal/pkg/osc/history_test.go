@@ -0,0 +1,117 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testHistory = `<revisionlist>
+  <revision rev="1" vrev="1">
+    <srcmd5>aaa</srcmd5>
+    <version>1.0</version>
+    <time>1700000000</time>
+    <user>alice</user>
+    <comment>initial import</comment>
+  </revision>
+  <revision rev="2" vrev="2">
+    <srcmd5>bbb</srcmd5>
+    <version>1.1</version>
+    <time>1700086400</time>
+    <user>bob</user>
+    <comment>- Update to 1.1
+- fix build</comment>
+  </revision>
+  <revision rev="3" vrev="3">
+    <srcmd5>ccc</srcmd5>
+    <version>1.1</version>
+    <time>1700172800</time>
+    <user>alice</user>
+    <comment>fix build</comment>
+  </revision>
+  <revision rev="4" vrev="4">
+    <srcmd5>ddd</srcmd5>
+    <version>1.1</version>
+    <time>1700259200</time>
+    <user>alice</user>
+    <comment></comment>
+  </revision>
+</revisionlist>`
+
+func newHistoryServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/source/home:testuser/testpackage/_history", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, testHistory)
+	}))
+}
+
+func TestSummarizeHistory(t *testing.T) {
+	server := newHistoryServer(t)
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.SummarizeHistory(context.Background(), &mcp.CallToolRequest{}, SummarizeHistoryParam{
+		ProjectName:  "home:testuser",
+		PackageName:  "testpackage",
+		FromRevision: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "4"}, result.Revisions)
+	assert.Equal(t, "- Update to 1.1\n- fix build", result.Summary)
+	assert.Contains(t, result.ChangesEntry, "\n\n- Update to 1.1\n- fix build\n\n")
+
+	_, _, err = cred.SummarizeHistory(context.Background(), &mcp.CallToolRequest{}, SummarizeHistoryParam{
+		ProjectName:  "home:testuser",
+		PackageName:  "testpackage",
+		FromRevision: 5,
+	})
+	assert.Error(t, err)
+}
+
+func TestGetPackageHistory(t *testing.T) {
+	server := newHistoryServer(t)
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.GetPackageHistory(context.Background(), &mcp.CallToolRequest{}, GetPackageHistoryParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Statistics:  true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Revisions, 4)
+	assert.Equal(t, "bob", result.Revisions[1].User)
+	assert.Equal(t, int64(1700086400), result.Revisions[1].Time)
+	assert.Equal(t, map[string]int{"alice": 3, "bob": 1}, result.Authors)
+	assert.Equal(t, "2023-11-14T22:13:20Z", result.Since)
+	assert.Equal(t, "2023-11-17T22:13:20Z", result.Until)
+
+	_, result, err = cred.GetPackageHistory(context.Background(), &mcp.CallToolRequest{}, GetPackageHistoryParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Statistics:  true,
+		Limit:       2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Total)
+	assert.Len(t, result.Revisions, 2)
+	assert.Equal(t, "3", result.Revisions[0].Rev)
+	assert.Equal(t, "4", result.Revisions[1].Rev)
+	assert.Equal(t, map[string]int{"alice": 3, "bob": 1}, result.Authors)
+}
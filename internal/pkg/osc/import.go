@@ -0,0 +1,126 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+type ImportProjectParam struct {
+	File          string `json:"file" jsonschema:"Manifest file written by export_project"`
+	TargetProject string `json:"target_project,omitempty" jsonschema:"Name of the project to create. Defaults to the project of the manifest."`
+	SkipExisting  bool   `json:"skip_existing,omitempty" jsonschema:"Don't update the meta of a project or package which already exists."`
+}
+
+type ImportProjectResult struct {
+	ProjectName string   `json:"project_name"`
+	Created     []string `json:"created" jsonschema:"Created project and packages"`
+	Updated     []string `json:"updated" jsonschema:"Existing project and packages whose meta was updated"`
+	Skipped     []string `json:"skipped" jsonschema:"Existing project and packages which were not changed"`
+}
+
+// renameMeta sets the project of a project or package meta. Repository paths
+// which point to the old project are changed as well.
+func renameMeta(meta, oldProject, newProject string) (string, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(meta); err != nil {
+		return "", fmt.Errorf("invalid meta XML: %w", err)
+	}
+	root := doc.Root()
+	if root == nil {
+		return "", fmt.Errorf("empty meta")
+	}
+	switch root.Tag {
+	case "project":
+		root.CreateAttr("name", newProject)
+		for _, path := range root.FindElements("repository/path") {
+			if path.SelectAttrValue("project", "") == oldProject {
+				path.CreateAttr("project", newProject)
+			}
+		}
+	case "package":
+		root.CreateAttr("project", newProject)
+	default:
+		return "", fmt.Errorf("unexpected meta root element <%s>", root.Tag)
+	}
+	return doc.WriteToString()
+}
+
+// importMeta writes the meta of a project or package and records the result.
+func (cred *OSCCredentials) importMeta(ctx context.Context, result *ImportProjectResult, packageName, meta string, skipExisting bool) error {
+	name := result.ProjectName
+	if packageName != "" {
+		name = fmt.Sprintf("%s/%s", result.ProjectName, packageName)
+	}
+	_, err := cred.getMetaDoc(ctx, result.ProjectName, packageName)
+	exists := err == nil
+	if err != nil && !errors.Is(err, ErrBundleOrProjectNotFound) {
+		return fmt.Errorf("failed to check %s: %w", name, err)
+	}
+	if exists && skipExisting {
+		result.Skipped = append(result.Skipped, name)
+		return nil
+	}
+	if _, err := cred.putMeta(ctx, result.ProjectName, packageName, meta); err != nil {
+		return fmt.Errorf("failed to write meta of %s: %w", name, err)
+	}
+	if exists {
+		result.Updated = append(result.Updated, name)
+	} else {
+		result.Created = append(result.Created, name)
+	}
+	return nil
+}
+
+func (cred *OSCCredentials) ImportProject(ctx context.Context, req *mcp.CallToolRequest, params ImportProjectParam) (*mcp.CallToolResult, *ImportProjectResult, error) {
+	slog.Debug("mcp tool call: ImportProject", "params", params)
+	if params.File == "" {
+		return nil, nil, fmt.Errorf("manifest file must be specified")
+	}
+	data, err := os.ReadFile(params.File)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	// JSON is valid YAML, so both formats of export_project are read here
+	var manifest ProjectExport
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.ProjectName == "" || manifest.Meta == "" {
+		return nil, nil, fmt.Errorf("manifest %s has no project meta", params.File)
+	}
+	target := params.TargetProject
+	if target == "" {
+		target = manifest.ProjectName
+	}
+
+	result := &ImportProjectResult{
+		ProjectName: target,
+		Created:     []string{},
+		Updated:     []string{},
+		Skipped:     []string{},
+	}
+	meta, err := renameMeta(manifest.Meta, manifest.ProjectName, target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid meta of project %s: %w", manifest.ProjectName, err)
+	}
+	if err := cred.importMeta(ctx, result, "", meta, params.SkipExisting); err != nil {
+		return nil, nil, err
+	}
+	for _, pkg := range manifest.Packages {
+		meta, err := renameMeta(pkg.Meta, manifest.ProjectName, target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid meta of package %s: %w", pkg.Name, err)
+		}
+		if err := cred.importMeta(ctx, result, pkg.Name, meta, params.SkipExisting); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nil, result, nil
+}
@@ -0,0 +1,69 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestImportProject(t *testing.T) {
+	var mu sync.Mutex
+	metas := map[string]string{
+		"/source/home:copy/foo/_meta": `<package name="foo" project="home:copy"/>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "GET":
+			meta, ok := metas[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, meta)
+		case "PUT":
+			body, _ := io.ReadAll(r.Body)
+			metas[r.URL.Path] = string(body)
+			io.WriteString(w, `<status code="ok"/>`)
+		}
+	}))
+	defer server.Close()
+
+	manifest := ProjectExport{
+		ProjectName: "home:testuser",
+		Meta:        `<project name="home:testuser"><title>Test</title><repository name="images"><path project="home:testuser" repository="standard"/></repository></project>`,
+		Packages: []PackageExport{
+			{Name: "foo", Meta: `<package name="foo" project="home:testuser"><title>foo</title></package>`},
+			{Name: "bar", Meta: `<package name="bar" project="home:testuser"><title>bar</title></package>`},
+		},
+	}
+	data, err := yaml.Marshal(manifest)
+	assert.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "home:testuser.manifest.yaml")
+	assert.NoError(t, os.WriteFile(file, data, 0644))
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.ImportProject(context.Background(), nil, ImportProjectParam{File: file, TargetProject: "home:copy", SkipExisting: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home:copy", "home:copy/bar"}, result.Created)
+	assert.Equal(t, []string{"home:copy/foo"}, result.Skipped)
+	assert.Empty(t, result.Updated)
+	assert.Equal(t, `<project name="home:copy"><title>Test</title><repository name="images"><path project="home:copy" repository="standard"/></repository></project>`, metas["/source/home:copy/_meta"])
+	assert.True(t, strings.Contains(metas["/source/home:copy/bar/_meta"], `project="home:copy"`))
+
+	// running it again only updates
+	_, result, err = cred.ImportProject(context.Background(), nil, ImportProjectParam{File: file, TargetProject: "home:copy"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"home:copy", "home:copy/foo", "home:copy/bar"}, result.Updated)
+}
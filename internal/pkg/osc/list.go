@@ -1,6 +1,9 @@
 package osc
 
 import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
@@ -9,6 +12,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,6 +20,7 @@ import (
 
 	"github.com/beevik/etree"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ulikunitz/xz"
 )
 
 var ErrBundleOrProjectNotFound = errors.New("bundle or project not found")
@@ -26,11 +31,67 @@ func commandFiles() []string {
 	return []string{".spec", ".kiwi", "Dockerfile", "_service", "_limits"}
 }
 
+// maxDecompressedSize is the maximal size of a decompressed file which is
+// returned as content.
+const maxDecompressedSize = 1024 * 1024
+
+// isBinary checks for null bytes in the first 1024 bytes of the content.
+func isBinary(content []byte) bool {
+	checkLen := min(len(content), 1024)
+	return bytes.IndexByte(content[:checkLen], 0) >= 0
+}
+
+// decompressContent decompresses a single compressed file like a patch.gz,
+// detected by the extension of its name. Archives like .tar.gz and files of
+// other types are returned unchanged.
+func decompressContent(name string, content []byte) ([]byte, bool, error) {
+	if strings.Contains(name, ".tar.") {
+		return content, false, nil
+	}
+	var reader io.Reader
+	var err error
+	switch filepath.Ext(name) {
+	case ".gz":
+		reader, err = gzip.NewReader(bytes.NewReader(content))
+	case ".bz2":
+		reader = bzip2.NewReader(bytes.NewReader(content))
+	case ".xz":
+		reader, err = xz.NewReader(bytes.NewReader(content))
+	default:
+		return content, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress %s: %w", name, err)
+	}
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress %s: %w", name, err)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, false, fmt.Errorf("decompressed content of %s is larger than %d bytes", name, maxDecompressedSize)
+	}
+	return decompressed, true, nil
+}
+
+// revisionState describes a local file relative to a remote revision.
+func revisionState(f FileInfoLocal) string {
+	switch {
+	case f.LocalOnly:
+		return "absent"
+	case f.Modified:
+		return "modified"
+	default:
+		return "matching"
+	}
+}
+
 type ListSrcFilesParam struct {
 	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
 	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
 	Local       bool   `json:"local,omitempty" jsonschema:"List source files of local bundle"`
 	Filename    string `json:"filename,omitempty" jsonschema:"Print content of file instead of all files in bundle."`
+	Revision    string `json:"revision,omitempty" jsonschema:"Compare the local files against this revision of the remote bundle instead of the latest one. Only valid for local bundles."`
+	Expand      bool   `json:"expand,omitempty" jsonschema:"List the expanded sources of a linked bundle, which contain the files of the link target, instead of the _link file and the patches of the link. Files with a _service: prefix are generated by server side services and are listed as they are stored on the server in both cases."`
 }
 
 type FileInfo struct {
@@ -39,11 +100,15 @@ type FileInfo struct {
 	MD5     string `json:"md5"`
 	MTime   string `json:"mtime"`
 	Content string `json:"content,omitempty"`
+	// Decompressed is set if the content of a compressed file was decompressed
+	Decompressed bool `json:"decompressed,omitempty"`
 }
 
 type FileInfoLocal struct {
 	Modified  bool `json:"modified,omitempty"`
 	LocalOnly bool `json:"localonly,omitempty"`
+	// RevisionState is only set if compared against a specific revision
+	RevisionState string `json:"revision_state,omitempty" jsonschema:"matching, modified or absent relative to the requested revision"`
 	FileInfo
 }
 
@@ -60,12 +125,30 @@ type ReturnedInfoRemote struct {
 type ReturnedInfoLocal struct {
 	ReturnedInfo
 	Local     bool            `json:"local" jsonschema:"Is local package"`
+	Revision  string          `json:"revision,omitempty" jsonschema:"Remote revision the files were compared against"`
 	LocalOnly bool            `json:"local_only" jsonschema:"File is only in the local repo"`
 	Files     []FileInfoLocal `json:"files" jsonschema:"List of files"`
 }
 
 func (cred *OSCCredentials) getRemoteList(ctx context.Context, projectName string, packageName string) ([]FileInfo, error) {
+	return cred.getRemoteListRev(ctx, projectName, packageName, "", false)
+}
+
+// getRemoteListRev lists the files of the given revision of a package, an
+// empty revision is the latest one. If expand is set, the expanded sources of
+// a link are listed.
+func (cred *OSCCredentials) getRemoteListRev(ctx context.Context, projectName, packageName, revision string, expand bool) ([]FileInfo, error) {
+	queryParams := url.Values{}
+	if revision != "" {
+		queryParams.Set("rev", revision)
+	}
+	if expand {
+		queryParams.Set("expand", "1")
+	}
 	path := fmt.Sprintf("source/%s/%s", projectName, packageName)
+	if len(queryParams) > 0 {
+		path += "?" + queryParams.Encode()
+	}
 	resp, err := cred.apiGetRequest(ctx, path, map[string]string{"Accept": "application/xml; charset=utf-8"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get remote file list: %w", err)
@@ -98,7 +181,16 @@ func (cred *OSCCredentials) getRemoteList(ctx context.Context, projectName strin
 }
 
 func (cred *OSCCredentials) getRemoteFileContent(ctx context.Context, projectName, packageName, fileName string) ([]byte, error) {
+	return cred.getRemoteFileContentExpanded(ctx, projectName, packageName, fileName, false)
+}
+
+// getRemoteFileContentExpanded reads a file of a package, which can also be a
+// file of the link target if expand is set.
+func (cred *OSCCredentials) getRemoteFileContentExpanded(ctx context.Context, projectName, packageName, fileName string, expand bool) ([]byte, error) {
 	path := fmt.Sprintf("source/%s/%s/%s", projectName, packageName, fileName)
+	if expand {
+		path += "?expand=1"
+	}
 	resp, err := cred.apiGetRequest(ctx, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get remote file content: %w", err)
@@ -120,6 +212,9 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 	if params.PackageName == "" {
 		return nil, nil, fmt.Errorf("package name cannot be empty")
 	}
+	if params.Revision != "" && !params.Local {
+		return nil, nil, fmt.Errorf("revision can only be used to compare local files")
+	}
 
 	if params.Filename != "" {
 		if params.Local {
@@ -129,15 +224,12 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 				return nil, nil, fmt.Errorf("failed to read local file %s: %w", params.Filename, err)
 			}
 
-			// Check for binary file (look for null bytes in the first 1024 bytes)
-			checkLen := 1024
-			if len(content) < checkLen {
-				checkLen = len(content)
+			content, decompressed, err := decompressContent(params.Filename, content)
+			if err != nil {
+				return nil, nil, err
 			}
-			for i := 0; i < checkLen; i++ {
-				if content[i] == 0 {
-					return nil, nil, fmt.Errorf("file %s is a binary file", params.Filename)
-				}
+			if isBinary(content) {
+				return nil, nil, fmt.Errorf("file %s is a binary file", params.Filename)
 			}
 
 			info, err := os.Stat(filePath)
@@ -151,15 +243,19 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 
 			f := FileInfoLocal{
 				FileInfo: FileInfo{
-					Name:    params.Filename,
-					Size:    fmt.Sprintf("%d", info.Size()),
-					MD5:     md5sum,
-					MTime:   fmt.Sprintf("%d", info.ModTime().Unix()),
-					Content: string(content),
+					Name:         params.Filename,
+					Size:         fmt.Sprintf("%d", info.Size()),
+					MD5:          md5sum,
+					MTime:        fmt.Sprintf("%d", info.ModTime().Unix()),
+					Content:      string(content),
+					Decompressed: decompressed,
 				},
 			}
 
-			remoteFiles, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+			remoteFiles, err := cred.getRemoteListRev(ctx, params.ProjectName, params.PackageName, params.Revision, params.Expand)
+			if err != nil && params.Revision != "" {
+				return nil, nil, fmt.Errorf("failed to get revision %s of remote bundle: %w", params.Revision, err)
+			}
 			isLocalOnlyPackage := false
 			if err != nil {
 				isLocalOnlyPackage = true
@@ -186,6 +282,9 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 					f.LocalOnly = true
 				}
 			}
+			if params.Revision != "" {
+				f.RevisionState = revisionState(f)
+			}
 
 			return nil, ReturnedInfoLocal{
 				ReturnedInfo: ReturnedInfo{
@@ -195,27 +294,25 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 				Files:     []FileInfoLocal{f},
 				Local:     true,
 				LocalOnly: isLocalOnlyPackage,
+				Revision:  params.Revision,
 			}, nil
 		}
 
 		// Remote file
-		content, err := cred.getRemoteFileContent(ctx, params.ProjectName, params.PackageName, params.Filename)
+		content, err := cred.getRemoteFileContentExpanded(ctx, params.ProjectName, params.PackageName, params.Filename, params.Expand)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get remote file content: %w", err)
 		}
 
-		// Check for binary file (look for null bytes in the first 1024 bytes)
-		checkLen := 1024
-		if len(content) < checkLen {
-			checkLen = len(content)
+		content, decompressed, err := decompressContent(params.Filename, content)
+		if err != nil {
+			return nil, nil, err
 		}
-		for i := 0; i < checkLen; i++ {
-			if content[i] == 0 {
-				return nil, nil, fmt.Errorf("file %s is a binary file", params.Filename)
-			}
+		if isBinary(content) {
+			return nil, nil, fmt.Errorf("file %s is a binary file", params.Filename)
 		}
 
-		files, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+		files, err := cred.getRemoteListRev(ctx, params.ProjectName, params.PackageName, "", params.Expand)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -235,6 +332,7 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 		}
 
 		fileInfo.Content = string(content)
+		fileInfo.Decompressed = decompressed
 
 		return nil, ReturnedInfoRemote{
 			ReturnedInfo: ReturnedInfo{
@@ -246,7 +344,10 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 	}
 
 	if params.Local {
-		remoteFiles, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+		remoteFiles, err := cred.getRemoteListRev(ctx, params.ProjectName, params.PackageName, params.Revision, params.Expand)
+		if err != nil && params.Revision != "" {
+			return nil, nil, fmt.Errorf("failed to get revision %s of remote bundle: %w", params.Revision, err)
+		}
 		if err != nil {
 			remoteFiles = []FileInfo{}
 			if !errors.Is(err, ErrBundleOrProjectNotFound) {
@@ -333,6 +434,9 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 					f.LocalOnly = true
 				}
 			}
+			if params.Revision != "" {
+				f.RevisionState = revisionState(f)
+			}
 			files = append(files, f)
 		}
 
@@ -344,10 +448,11 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 			Files:     files,
 			Local:     true,
 			LocalOnly: isLocalOnlyPackage,
+			Revision:  params.Revision,
 		}, nil
 	}
 
-	files, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+	files, err := cred.getRemoteListRev(ctx, params.ProjectName, params.PackageName, "", params.Expand)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -366,7 +471,7 @@ func (cred *OSCCredentials) ListSrcFiles(ctx context.Context, req *mcp.CallToolR
 			}
 		}
 		if isCmdFile || size < maxSize {
-			content, err := cred.getRemoteFileContent(ctx, params.ProjectName, params.PackageName, file.Name)
+			content, err := cred.getRemoteFileContentExpanded(ctx, params.ProjectName, params.PackageName, file.Name, params.Expand)
 			if err == nil {
 				file.Content = string(content)
 			}
@@ -0,0 +1,161 @@
+package osc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+func TestDecompressContent(t *testing.T) {
+	patch := []byte("--- a/foo\n+++ b/foo\n@@ -1 +1 @@\n-foo\n+bar\n")
+
+	var gz bytes.Buffer
+	gzWriter := gzip.NewWriter(&gz)
+	gzWriter.Write(patch)
+	gzWriter.Close()
+	content, decompressed, err := decompressContent("fix.patch.gz", gz.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, decompressed)
+	assert.Equal(t, patch, content)
+
+	var xzBuf bytes.Buffer
+	xzWriter, err := xz.NewWriter(&xzBuf)
+	assert.NoError(t, err)
+	xzWriter.Write(patch)
+	xzWriter.Close()
+	content, decompressed, err = decompressContent("fix.patch.xz", xzBuf.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, decompressed)
+	assert.Equal(t, patch, content)
+
+	// archives are kept as they are
+	content, decompressed, err = decompressContent("foo-1.0.tar.gz", gz.Bytes())
+	assert.NoError(t, err)
+	assert.False(t, decompressed)
+	assert.Equal(t, gz.Bytes(), content)
+	assert.True(t, isBinary(content))
+
+	content, decompressed, err = decompressContent("foo.spec", patch)
+	assert.NoError(t, err)
+	assert.False(t, decompressed)
+	assert.Equal(t, patch, content)
+	assert.False(t, isBinary(content))
+
+	_, _, err = decompressContent("broken.gz", patch)
+	assert.Error(t, err)
+}
+
+func TestListSrcFilesRevision(t *testing.T) {
+	tempDir := t.TempDir()
+	dir := filepath.Join(tempDir, "home:testuser", "testpackage")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testpackage.spec"), []byte("Name: testpackage\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fix.patch"), []byte("fix\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "new.patch"), []byte("new\n"), 0644))
+	fixMd5, err := fileMD5(filepath.Join(dir, "fix.patch"))
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/source/home:testuser/testpackage" || r.URL.Query().Get("rev") != "12" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `<directory name="testpackage" rev="12">
+  <entry name="testpackage.spec" md5="00000000000000000000000000000000" size="1" mtime="1"/>
+  <entry name="fix.patch" md5="%s" size="4" mtime="1"/>
+</directory>`, fixMd5)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, TempDir: tempDir}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}}
+	_, result, err := cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Local:       true,
+		Revision:    "12",
+	})
+	assert.NoError(t, err)
+	info := result.(ReturnedInfoLocal)
+	assert.Equal(t, "12", info.Revision)
+	states := make(map[string]string)
+	for _, f := range info.Files {
+		states[f.Name] = f.RevisionState
+	}
+	assert.Equal(t, map[string]string{
+		"fix.patch":        "matching",
+		"new.patch":        "absent",
+		"testpackage.spec": "modified",
+	}, states)
+
+	_, _, err = cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Local:       true,
+		Revision:    "13",
+	})
+	assert.ErrorIs(t, err, ErrBundleOrProjectNotFound)
+
+	_, _, err = cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Revision:    "12",
+	})
+	assert.Error(t, err)
+}
+
+func TestListSrcFilesExpand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expand := r.URL.Query().Get("expand") == "1"
+		switch {
+		case r.URL.Path == "/source/home:testuser/linked" && expand:
+			fmt.Fprint(w, `<directory name="linked">
+  <entry name="linked.spec" md5="00000000000000000000000000000000" size="16" mtime="1"/>
+  <entry name="fix.patch" md5="00000000000000000000000000000001" size="4" mtime="1"/>
+</directory>`)
+		case r.URL.Path == "/source/home:testuser/linked":
+			fmt.Fprint(w, `<directory name="linked">
+  <entry name="_link" md5="00000000000000000000000000000002" size="10" mtime="1"/>
+</directory>`)
+		case r.URL.Path == "/source/home:testuser/linked/linked.spec" && expand:
+			fmt.Fprint(w, "Name: linked\n")
+		case r.URL.Path == "/source/home:testuser/linked/fix.patch" && expand:
+			fmt.Fprint(w, "fix\n")
+		case r.URL.Path == "/source/home:testuser/linked/_link":
+			fmt.Fprint(w, `<link project="openSUSE:Factory"/>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, TempDir: t.TempDir()}
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}}
+	_, result, err := cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{ProjectName: "home:testuser", PackageName: "linked"})
+	assert.NoError(t, err)
+	files := result.(ReturnedInfoRemote).Files
+	assert.Len(t, files, 1)
+	assert.Equal(t, "_link", files[0].Name)
+
+	_, result, err = cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{ProjectName: "home:testuser", PackageName: "linked", Expand: true})
+	assert.NoError(t, err)
+	files = result.(ReturnedInfoRemote).Files
+	assert.Len(t, files, 2)
+	assert.Equal(t, "linked.spec", files[0].Name)
+	assert.Equal(t, "Name: linked\n", files[0].Content)
+
+	_, result, err = cred.ListSrcFiles(context.Background(), req, ListSrcFilesParam{ProjectName: "home:testuser", PackageName: "linked", Filename: "fix.patch", Expand: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "fix\n", result.(ReturnedInfoRemote).Files[0].Content)
+}
@@ -0,0 +1,170 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// metaPath returns the api path of the project meta, or of the package meta if
+// packageName isn't empty.
+func metaPath(projectName, packageName string) string {
+	if packageName != "" {
+		return fmt.Sprintf("source/%s/%s/_meta", projectName, packageName)
+	}
+	return fmt.Sprintf("source/%s/_meta", projectName)
+}
+
+// getMetaDoc reads the raw meta of a project or package.
+func (cred *OSCCredentials) getMetaDoc(ctx context.Context, projectName, packageName string) (*etree.Document, error) {
+	resp, err := cred.apiGetRequest(ctx, metaPath(projectName, packageName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return doc, nil
+}
+
+// putMeta writes the raw meta of a project or package and returns the answer
+// of the server.
+func (cred *OSCCredentials) putMeta(ctx context.Context, projectName, packageName, meta string) (string, error) {
+	apiURL := fmt.Sprintf("%s/%s", cred.GetAPiAddr(), metaPath(projectName, packageName))
+	httpReq, err := cred.buildRequest(ctx, "PUT", apiURL, strings.NewReader(meta))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return string(body), fmt.Errorf("api request failed with status: %s\nbody:\n%s", resp.Status, string(body))
+	}
+	return string(body), nil
+}
+
+// putMetaDoc writes the meta document of a project or package.
+func (cred *OSCCredentials) putMetaDoc(ctx context.Context, projectName, packageName string, doc *etree.Document) error {
+	doc.Indent(2)
+	metaString, err := doc.WriteToString()
+	if err != nil {
+		return fmt.Errorf("failed to generate XML: %w", err)
+	}
+	_, err = cred.putMeta(ctx, projectName, packageName, metaString)
+	return err
+}
+
+// ErrScmSync is returned if sources are changed which are synchronized from git.
+var ErrScmSync = errors.New("the sources are managed in git with scmsync, commit the changes to the git repository instead")
+
+// getScmSync returns the scmsync URL of a package or of its project if the
+// package meta doesn't have one. An empty string means the sources are
+// managed by OBS.
+func (cred *OSCCredentials) getScmSync(ctx context.Context, projectName, packageName string) (string, error) {
+	for _, pkg := range []string{packageName, ""} {
+		doc, err := cred.getMetaDoc(ctx, projectName, pkg)
+		if errors.Is(err, ErrBundleOrProjectNotFound) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		if root := doc.Root(); root != nil {
+			if scmsync := root.SelectElement("scmsync"); scmsync != nil && strings.TrimSpace(scmsync.Text()) != "" {
+				return strings.TrimSpace(scmsync.Text()), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+type GetRawMetaParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Name of the bundle. If empty, the meta of the project is returned."`
+}
+
+type RawMetaResult struct {
+	Meta string `json:"meta"`
+}
+
+func (cred *OSCCredentials) GetRawMeta(ctx context.Context, req *mcp.CallToolRequest, params GetRawMetaParam) (*mcp.CallToolResult, *RawMetaResult, error) {
+	slog.Debug("mcp tool call: GetRawMeta", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	resp, err := cred.apiGetRequest(ctx, metaPath(params.ProjectName, params.PackageName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return nil, &RawMetaResult{Meta: string(body)}, nil
+}
+
+type SetRawMetaParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Name of the bundle. If empty, the meta of the project is set."`
+	Meta        string `json:"meta" jsonschema:"Complete meta XML which replaces the existing meta."`
+}
+
+type SetRawMetaResult struct {
+	Response string `json:"response"`
+}
+
+func (cred *OSCCredentials) SetRawMeta(ctx context.Context, req *mcp.CallToolRequest, params SetRawMetaParam) (*mcp.CallToolResult, *SetRawMetaResult, error) {
+	slog.Debug("mcp tool call: SetRawMeta", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if strings.TrimSpace(params.Meta) == "" {
+		return nil, nil, fmt.Errorf("meta cannot be empty")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(params.Meta); err != nil {
+		return nil, nil, fmt.Errorf("meta is not well-formed XML: %w", err)
+	}
+	root := doc.Root()
+	expected := "project"
+	if params.PackageName != "" {
+		expected = "package"
+	}
+	if root == nil || root.Tag != expected {
+		return nil, nil, fmt.Errorf("meta must have a <%s> root element", expected)
+	}
+
+	response, err := cred.putMeta(ctx, params.ProjectName, params.PackageName, params.Meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &SetRawMetaResult{Response: response}, nil
+}
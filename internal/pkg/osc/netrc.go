@@ -0,0 +1,102 @@
+package osc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcPath returns the path of the netrc file, which is $NETRC or ~/.netrc.
+func netrcPath() string {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// readNetrc returns the login and password of the machine entry for host in
+// a netrc file. The default entry is used if there is no entry for host. A
+// port of host is ignored if there is no entry with the port.
+func readNetrc(path, host string) (login, password string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	type entry struct{ login, password string }
+	machines := make(map[string]*entry)
+	var current, fallback *entry
+	scanner := bufio.NewScanner(file)
+	inMacro := false
+	var tokens []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		// a macro definition ends with an empty line
+		if inMacro {
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		tokens = append(tokens, strings.Fields(line)...)
+		for len(tokens) > 0 {
+			keyword := tokens[0]
+			switch keyword {
+			case "default":
+				current = &entry{}
+				fallback = current
+				tokens = tokens[1:]
+				continue
+			case "macdef":
+				inMacro = true
+				tokens = nil
+				continue
+			}
+			if len(tokens) < 2 {
+				break
+			}
+			value := tokens[1]
+			tokens = tokens[2:]
+			switch keyword {
+			case "machine":
+				current = &entry{}
+				if _, ok := machines[value]; !ok {
+					machines[value] = current
+				}
+			case "login":
+				if current != nil {
+					current.login = value
+				}
+			case "password":
+				if current != nil {
+					current.password = value
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	found, ok := machines[host]
+	if !ok {
+		if i := strings.LastIndex(host, ":"); i > 0 {
+			found, ok = machines[host[:i]]
+		}
+	}
+	if !ok {
+		found = fallback
+	}
+	if found == nil || found.password == "" {
+		return "", "", fmt.Errorf("no password for %s in %s", host, path)
+	}
+	return found.login, found.password, nil
+}
@@ -0,0 +1,66 @@
+package osc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadNetrc(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "netrc")
+	assert.NoError(t, os.WriteFile(path, []byte(`# build service
+machine api.opensuse.org login alice password secret1
+machine obs.example.com
+  login bob
+  password secret2
+
+macdef init
+machine api.opensuse.org login mallory password wrong
+
+machine nologin.example.com password secret3
+default login anonymous password guest
+`), 0600))
+
+	for host, expected := range map[string][2]string{
+		"api.opensuse.org":     {"alice", "secret1"},
+		"obs.example.com:3000": {"bob", "secret2"},
+		"nologin.example.com":  {"", "secret3"},
+		"other.example.com":    {"anonymous", "guest"},
+	} {
+		login, password, err := readNetrc(path, host)
+		assert.NoError(t, err, host)
+		assert.Equal(t, expected, [2]string{login, password}, host)
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte("machine api.opensuse.org login alice\n"), 0600))
+	_, _, err := readNetrc(path, "api.opensuse.org")
+	assert.Error(t, err)
+}
+
+func TestGetCredentialsNetrc(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Chdir(home)
+	netrc := filepath.Join(home, "netrc")
+	assert.NoError(t, os.WriteFile(netrc, []byte("machine obs.example.com login alice password secret\n"), 0600))
+	t.Setenv("NETRC", netrc)
+	viper.Set("api", "https://obs.example.com")
+	defer viper.Reset()
+
+	creds, err := GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", creds.Name)
+	assert.Equal(t, "secret", creds.Passwd)
+
+	// the password of the command line is used before the one of netrc
+	viper.Set("user", "bob")
+	viper.Set("password", "cli")
+	creds, err = GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", creds.Name)
+	assert.Equal(t, "cli", creds.Passwd)
+}
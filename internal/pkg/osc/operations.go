@@ -0,0 +1,134 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Operation is a long running tool call which can be cancelled.
+type Operation struct {
+	Id        string `json:"id"`
+	Tool      string `json:"tool"`
+	SessionId string `json:"session_id,omitempty"`
+	Started   string `json:"started"`
+	cancel    context.CancelFunc
+}
+
+// operationRegistry tracks the running operations of all sessions.
+type operationRegistry struct {
+	mu         sync.Mutex
+	lastId     int
+	operations map[string]*Operation
+}
+
+func newOperationRegistry() *operationRegistry {
+	return &operationRegistry{operations: make(map[string]*Operation)}
+}
+
+func sessionId(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	return req.Session.ID()
+}
+
+// startOperation registers a cancellable operation for the tool call. The
+// returned context must be used for all commands and requests of the
+// operation and the returned function must be called when it has finished.
+func (cred *OSCCredentials) startOperation(ctx context.Context, req *mcp.CallToolRequest, tool string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	if cred.operations == nil {
+		return ctx, cancel
+	}
+	reg := cred.operations
+	reg.mu.Lock()
+	reg.lastId++
+	op := &Operation{
+		Id:        strconv.Itoa(reg.lastId),
+		Tool:      tool,
+		SessionId: sessionId(req),
+		Started:   time.Now().Format(time.RFC3339),
+		cancel:    cancel,
+	}
+	reg.operations[op.Id] = op
+	reg.mu.Unlock()
+	slog.Debug("operation started", "id", op.Id, "tool", tool)
+	return ctx, func() {
+		reg.mu.Lock()
+		delete(reg.operations, op.Id)
+		reg.mu.Unlock()
+		cancel()
+	}
+}
+
+// sessionOperations returns the operations visible to the given session, which
+// are all operations if the session is unknown.
+func (reg *operationRegistry) sessionOperations(session string) []*Operation {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	ops := []*Operation{}
+	for _, op := range reg.operations {
+		if session == "" || op.SessionId == session {
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		a, _ := strconv.Atoi(ops[i].Id)
+		b, _ := strconv.Atoi(ops[j].Id)
+		return a < b
+	})
+	return ops
+}
+
+type ListOperationsParam struct{}
+
+type ListOperationsResult struct {
+	Operations []Operation `json:"operations"`
+}
+
+func (cred *OSCCredentials) ListOperations(ctx context.Context, req *mcp.CallToolRequest, params ListOperationsParam) (*mcp.CallToolResult, *ListOperationsResult, error) {
+	slog.Debug("mcp tool call: ListOperations", "params", params)
+	result := &ListOperationsResult{Operations: []Operation{}}
+	if cred.operations == nil {
+		return nil, result, nil
+	}
+	for _, op := range cred.operations.sessionOperations(sessionId(req)) {
+		result.Operations = append(result.Operations, *op)
+	}
+	return nil, result, nil
+}
+
+type CancelOperationParam struct {
+	Id string `json:"id" jsonschema:"Id of the operation as returned by list_operations"`
+}
+
+type CancelOperationResult struct {
+	Id        string `json:"id"`
+	Tool      string `json:"tool"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+func (cred *OSCCredentials) CancelOperation(ctx context.Context, req *mcp.CallToolRequest, params CancelOperationParam) (*mcp.CallToolResult, *CancelOperationResult, error) {
+	slog.Debug("mcp tool call: CancelOperation", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("operation id must be specified")
+	}
+	if cred.operations == nil {
+		return nil, nil, fmt.Errorf("no running operation with id %s", params.Id)
+	}
+	for _, op := range cred.operations.sessionOperations(sessionId(req)) {
+		if op.Id == params.Id {
+			op.cancel()
+			slog.Info("operation cancelled", "id", op.Id, "tool", op.Tool)
+			return nil, &CancelOperationResult{Id: op.Id, Tool: op.Tool, Cancelled: true}, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no running operation with id %s", params.Id)
+}
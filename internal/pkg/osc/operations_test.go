@@ -0,0 +1,37 @@
+package osc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperations(t *testing.T) {
+	cred := &OSCCredentials{operations: newOperationRegistry()}
+	req := &mcp.CallToolRequest{}
+
+	buildCtx, buildDone := cred.startOperation(context.Background(), req, "run_build")
+	_, checkoutDone := cred.startOperation(context.Background(), req, "checkout_bundle")
+
+	_, list, err := cred.ListOperations(context.Background(), req, ListOperationsParam{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Operations, 2)
+	assert.Equal(t, "run_build", list.Operations[0].Tool)
+	assert.Equal(t, "checkout_bundle", list.Operations[1].Tool)
+
+	_, result, err := cred.CancelOperation(context.Background(), req, CancelOperationParam{Id: list.Operations[0].Id})
+	assert.NoError(t, err)
+	assert.True(t, result.Cancelled)
+	assert.ErrorIs(t, buildCtx.Err(), context.Canceled)
+
+	buildDone()
+	checkoutDone()
+	_, list, err = cred.ListOperations(context.Background(), req, ListOperationsParam{})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Operations)
+
+	_, _, err = cred.CancelOperation(context.Background(), req, CancelOperationParam{Id: "1"})
+	assert.Error(t, err)
+}
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/jsipprell/keyctl"
@@ -25,15 +28,27 @@ func IgnoredDirs() []string {
 }
 
 type OSCCredentials struct {
-	Name               string
-	EMail              string
-	Passwd             string
-	Apiaddr            string
-	TempDir            string
-	BuildLogs          map[string]*buildlog.BuildLog
-	LastBuildKey       string
-	buildRootInWorkdir bool
-	useInternalCommit  bool
+	Name                 string
+	EMail                string
+	Passwd               string
+	Token                string
+	Apiaddr              string
+	TempDir              string
+	MaxLogLines          int
+	Concurrency          int
+	MaxUploadConcurrency int
+	Retries              int
+	IndexTTL             time.Duration
+	EnabledTools         []string
+	BuildLogs            map[string]*buildlog.BuildLog
+	LastBuildKey         string
+	buildRootInWorkdir   bool
+	// keyringDomain is set if the password was read from the keyring, it is
+	// read again for this domain if the api rejects it
+	keyringDomain     string
+	useInternalCommit bool
+	operations        *operationRegistry
+	httpClient        *http.Client
 }
 
 func (cred *OSCCredentials) GetAPiAddr() string {
@@ -43,20 +58,38 @@ func (cred *OSCCredentials) GetAPiAddr() string {
 	return fmt.Sprintf("https://%s", cred.Apiaddr)
 }
 
+// GetWebAddr returns the address of the web interface, which is the api
+// address with the api. prefix replaced by build.
+func (cred *OSCCredentials) GetWebAddr() string {
+	scheme := "https://"
+	if strings.HasPrefix(cred.Apiaddr, "http://") {
+		scheme = "http://"
+	}
+	domain := cred.GetApiDomain()
+	if strings.HasPrefix(domain, "api.") {
+		domain = "build." + strings.TrimPrefix(domain, "api.")
+	}
+	return scheme + domain
+}
+
 func (cred *OSCCredentials) GetApiDomain() string {
 	addr := strings.TrimPrefix(cred.Apiaddr, "https://")
 	addr = strings.TrimPrefix(addr, "http://")
-	return addr
+	return strings.TrimSuffix(addr, "/")
 }
 
 // GetCredentials reads the osc configuration, determines the api url and
 // returns the stored credentials.
 // It will try to read ~/.config/osc/oscrc, ~/.oscrc and ./.oscrc.
 // It first tries to read the user and password from the config file. If a
-// password is not found, it will try to read the credentials from the keyring.
+// password is not found, it will try to read the credentials from ~/.netrc and
+// then from the keyring.
+// A token from the command line or the config file is used instead of the
+// password.
 func GetCredentials() (OSCCredentials, error) {
 	creds := OSCCredentials{
-		BuildLogs: make(map[string]*buildlog.BuildLog),
+		BuildLogs:  make(map[string]*buildlog.BuildLog),
+		operations: newOperationRegistry(),
 	}
 	var configPath string
 	home, err := os.UserHomeDir()
@@ -90,6 +123,12 @@ func GetCredentials() (OSCCredentials, error) {
 	if creds.Apiaddr == "" {
 		creds.Apiaddr = "api.opensuse.org"
 	}
+	creds.MaxLogLines = viper.GetInt("max-log-lines")
+	creds.Concurrency = viper.GetInt("concurrency")
+	creds.MaxUploadConcurrency = viper.GetInt("max-upload-concurrency")
+	creds.Retries = viper.GetInt("retries")
+	creds.IndexTTL = viper.GetDuration("index-ttl")
+	creds.httpClient = newHTTPClient(viper.GetDuration("http-timeout"))
 	if viper.GetString("email") != "" {
 		creds.EMail = viper.GetString("email")
 	} else {
@@ -121,6 +160,16 @@ func GetCredentials() (OSCCredentials, error) {
 	if viper.IsSet("password") {
 		pass = viper.GetString("password")
 	}
+	token := cfg.GetString(creds.Apiaddr, "token")
+	if viper.IsSet("token") {
+		token = viper.GetString("token")
+	}
+	if token != "" {
+		creds.Name = user
+		creds.Token = token
+		slog.Info("Loaded token", "user", user, "api", creds.Apiaddr)
+		return creds, nil
+	}
 	if pass != "" {
 		if user == "" {
 			return creds, fmt.Errorf("user not set for apiurl %s in .oscrc", creds.Apiaddr)
@@ -131,6 +180,23 @@ func GetCredentials() (OSCCredentials, error) {
 		return creds, nil
 	}
 
+	if path := netrcPath(); path != "" {
+		if login, netrcPass, err := readNetrc(path, creds.GetApiDomain()); err == nil {
+			if login != "" {
+				user = login
+			}
+			if user == "" {
+				return creds, fmt.Errorf("password found in %s for %s, but user is missing from both netrc and config", path, creds.Apiaddr)
+			}
+			creds.Name = user
+			creds.Passwd = netrcPass
+			slog.Info("Loaded credentials from netrc", "user", user, "api", creds.Apiaddr, "path", path)
+			return creds, nil
+		} else if !os.IsNotExist(err) {
+			slog.Debug("no credentials in netrc", "path", path, "error", err)
+		}
+	}
+
 	// Check for kernel keyring (keyutils) before D-Bus
 	var keyringCreds OSCCredentials
 	slog.Debug("Password not in config, attempting kernel keyring (keyutils)")
@@ -142,6 +208,7 @@ func GetCredentials() (OSCCredentials, error) {
 		if err != nil {
 			return creds, fmt.Errorf("password not found in %s and keyring access failed: %w", configPath, err)
 		}
+		creds.keyringDomain = creds.GetApiDomain()
 	}
 
 	creds.Passwd = keyringCreds.Passwd
@@ -213,7 +280,51 @@ func useKernelKeyringCreds() (cred OSCCredentials, err error) {
 	return cred, fmt.Errorf("no readable keys found in 'osc_credentials' keyring")
 }
 
-func useKeyringCreds(apiAddr string) (cred OSCCredentials, err error) {
+// keyringCacheTTL is the time for which credentials read from the keyring are
+// used without asking the secret service again.
+const keyringCacheTTL = 5 * time.Minute
+
+type keyringCacheEntry struct {
+	cred    OSCCredentials
+	expires time.Time
+}
+
+var (
+	keyringCacheMu sync.Mutex
+	keyringCache   = make(map[string]keyringCacheEntry)
+	// keyringLookup reads the credentials from the secret service, it is
+	// replaced in the tests.
+	keyringLookup = lookupKeyringCreds
+)
+
+// useKeyringCreds returns the credentials of the keyring for apiAddr. They are
+// cached for keyringCacheTTL, as the lookup opens a D-Bus session and searches
+// all collections.
+func useKeyringCreds(apiAddr string) (OSCCredentials, error) {
+	keyringCacheMu.Lock()
+	defer keyringCacheMu.Unlock()
+	if entry, ok := keyringCache[apiAddr]; ok && time.Now().Before(entry.expires) {
+		slog.Debug("Using cached keyring credentials", "api", apiAddr)
+		return entry.cred, nil
+	}
+	cred, err := keyringLookup(apiAddr)
+	if err != nil {
+		return cred, err
+	}
+	keyringCache[apiAddr] = keyringCacheEntry{cred: cred, expires: time.Now().Add(keyringCacheTTL)}
+	return cred, nil
+}
+
+// forgetKeyringCreds drops the cached keyring credentials of apiAddr, so that
+// a changed password is read again after an authentication failure, see
+// refreshKeyringPassword.
+func forgetKeyringCreds(apiAddr string) {
+	keyringCacheMu.Lock()
+	defer keyringCacheMu.Unlock()
+	delete(keyringCache, apiAddr)
+}
+
+func lookupKeyringCreds(apiAddr string) (cred OSCCredentials, err error) {
 	bus, err := dbus.SessionBus()
 	cred.Apiaddr = apiAddr
 	if err != nil {
@@ -266,7 +377,8 @@ var ErrNoUserOrPassword = errors.New("bundle or project not found")
 // writeTempOscConfig creates a temporary osc configuration file with credentials
 // and returns the path to the file. It's the caller's responsibility to remove the file.
 func (cred *OSCCredentials) writeTempOscConfig() (string, error) {
-	if cred.Name == "" || cred.Passwd == "" {
+	passwd := cred.password()
+	if cred.Name == "" || passwd == "" {
 		// No credentials, so no config file needed.
 		// The command will use the default config.
 		return "", ErrNoUserOrPassword
@@ -281,7 +393,7 @@ func (cred *OSCCredentials) writeTempOscConfig() (string, error) {
 		return "", fmt.Errorf("workdir %s is part of credential tempdir %s", cred.TempDir, configFile.Name())
 	}
 
-	configContent := fmt.Sprintf("[general]\napi=%s\nuser=%s\n[%s]\nuser=%s\npass=%s\n", cred.GetAPiAddr(), cred.Name, cred.GetAPiAddr(), cred.Name, cred.Passwd)
+	configContent := fmt.Sprintf("[general]\napi=%s\nuser=%s\n[%s]\nuser=%s\npass=%s\n", cred.GetAPiAddr(), cred.Name, cred.GetAPiAddr(), cred.Name, passwd)
 	slog.Debug("configuration file content", "content", configContent)
 	if _, err := configFile.WriteString(configContent); err != nil {
 		configFile.Close() // Close the file before removing it.
@@ -296,16 +408,133 @@ func (cred *OSCCredentials) writeTempOscConfig() (string, error) {
 	return configFile.Name(), nil
 }
 
+// defaultHTTPTimeout is used if no --http-timeout is set
+const defaultHTTPTimeout = 10 * time.Minute
+
+// newHTTPClient returns the client for the requests to the build service.
+// The timeout limits a whole request including the transfer of the body, a
+// timeout of 0 uses defaultHTTPTimeout and a negative one disables it.
+// Requests can still be cancelled by their context.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	} else if timeout < 0 {
+		timeout = 0
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   16,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// client returns the shared http client, which is only missing if the
+// credentials weren't created by GetCredentials.
+func (cred *OSCCredentials) client() *http.Client {
+	if cred.httpClient == nil {
+		return http.DefaultClient
+	}
+	return cred.httpClient
+}
+
 func (cred *OSCCredentials) buildRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 	return req, nil
 }
 
+// setAuth authenticates a request with the token if there is one and with
+// the user and password otherwise.
+func (cred *OSCCredentials) setAuth(req *http.Request) {
+	if cred.Token != "" {
+		req.Header.Set("Authorization", "Token "+cred.Token)
+		return
+	}
+	req.SetBasicAuth(cred.Name, cred.password())
+}
+
+// passwdMu guards the password, which is replaced when it was changed in
+// the keyring.
+var passwdMu sync.RWMutex
+
+func (cred *OSCCredentials) password() string {
+	passwdMu.RLock()
+	defer passwdMu.RUnlock()
+	return cred.Passwd
+}
+
+// refreshKeyringPassword reads the password from the keyring again after the
+// api rejected the password used, and reports if it changed.
+func (cred *OSCCredentials) refreshKeyringPassword(used string) bool {
+	if cred.keyringDomain == "" || cred.Token != "" {
+		return false
+	}
+	passwdMu.Lock()
+	defer passwdMu.Unlock()
+	if cred.Passwd != used {
+		// another request already read the new password
+		return true
+	}
+	forgetKeyringCreds(cred.keyringDomain)
+	keyringCred, err := useKeyringCreds(cred.keyringDomain)
+	if err != nil {
+		slog.Warn("failed to read the password from the keyring again", "api", cred.keyringDomain, "error", err)
+		return false
+	}
+	if keyringCred.Passwd == used {
+		return false
+	}
+	slog.Info("Password changed in the keyring", "api", cred.keyringDomain)
+	cred.Passwd = keyringCred.Passwd
+	return true
+}
+
+// do sends a request to the api. If the api rejects a password of the
+// keyring, the password is read again and the request is retried once with
+// the new one.
+func (cred *OSCCredentials) do(req *http.Request) (*http.Response, error) {
+	resp, err := cred.client().Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || cred.keyringDomain == "" {
+		return resp, err
+	}
+	_, used, _ := req.BasicAuth()
+	if !cred.refreshKeyringPassword(used) {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	cred.setAuth(retry)
+	slog.Debug("retrying request with the new password", "url", req.URL)
+	return cred.client().Do(retry)
+}
+
 func (cred *OSCCredentials) apiGetRequest(ctx context.Context, path string, headers map[string]string) (*http.Response, error) {
 	apiURL := fmt.Sprintf("%s/%s", cred.GetAPiAddr(), path)
 	slog.Debug("API GET request", "url", apiURL, "path", path)
@@ -318,8 +547,7 @@ func (cred *OSCCredentials) apiGetRequest(ctx context.Context, path string, head
 		req.Header.Set(k, v)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		slog.Error("API request failed", "url", apiURL, "error", err)
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -0,0 +1,173 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClientTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	cred := &OSCCredentials{Apiaddr: server.URL, httpClient: newHTTPClient(50 * time.Millisecond)}
+	_, err := cred.apiGetRequest(context.Background(), "about", nil)
+	assert.ErrorContains(t, err, "Client.Timeout")
+
+	assert.Equal(t, defaultHTTPTimeout, newHTTPClient(0).Timeout)
+	cred.httpClient = newHTTPClient(-1)
+	assert.Zero(t, cred.httpClient.Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = cred.apiGetRequest(ctx, "about", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetApiDomain(t *testing.T) {
+	testCases := []struct {
+		apiaddr  string
+		expected string
+	}{
+		{"api.opensuse.org", "api.opensuse.org"},
+		{"api.opensuse.org/", "api.opensuse.org"},
+		{"https://api.opensuse.org", "api.opensuse.org"},
+		{"https://api.opensuse.org/", "api.opensuse.org"},
+		{"http://localhost:3000", "localhost:3000"},
+		{"http://localhost:3000/", "localhost:3000"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.apiaddr, func(t *testing.T) {
+			cred := &OSCCredentials{Apiaddr: tc.apiaddr}
+			assert.Equal(t, tc.expected, cred.GetApiDomain())
+		})
+	}
+}
+
+func TestGetWebAddr(t *testing.T) {
+	for apiAddr, expected := range map[string]string{
+		"api.opensuse.org":      "https://build.opensuse.org",
+		"https://api.suse.de/":  "https://build.suse.de",
+		"http://localhost:3000": "http://localhost:3000",
+		"obs.example.com":       "https://obs.example.com",
+	} {
+		cred := OSCCredentials{Apiaddr: apiAddr}
+		assert.Equal(t, expected, cred.GetWebAddr(), apiAddr)
+	}
+}
+
+func TestAuthHeader(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	resp, err := cred.apiGetRequest(context.Background(), "about", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Basic dGVzdHVzZXI6dGVzdHBhc3N3b3Jk", auth)
+
+	cred.Token = "secret-token"
+	resp, err = cred.apiGetRequest(context.Background(), "about", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Token secret-token", auth)
+
+	_, _, err = cred.getFromApi(context.Background(), server.URL+"/about")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token secret-token", auth)
+}
+
+func TestKeyringCache(t *testing.T) {
+	lookups := 0
+	keyringLookup = func(apiAddr string) (OSCCredentials, error) {
+		lookups++
+		passwd := "password1"
+		if lookups > 1 {
+			passwd = "password2"
+		}
+		return OSCCredentials{Apiaddr: apiAddr, Name: "testuser", Passwd: passwd}, nil
+	}
+	defer func() { keyringLookup = lookupKeyringCreds }()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, passwd, _ := r.BasicAuth(); passwd != "password2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+	cred := &OSCCredentials{Name: "testuser", Passwd: "password1", Apiaddr: server.URL}
+	cred.keyringDomain = cred.GetApiDomain()
+	forgetKeyringCreds(cred.keyringDomain)
+
+	for range 3 {
+		keyringCred, err := useKeyringCreds(cred.keyringDomain)
+		assert.NoError(t, err)
+		assert.Equal(t, "password1", keyringCred.Passwd)
+	}
+	assert.Equal(t, 1, lookups)
+
+	// an authentication failure reads the changed password and retries
+	resp, err := cred.apiGetRequest(context.Background(), "about", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "password2", cred.Passwd)
+	assert.Equal(t, 2, lookups)
+
+	// requests with a body are retried as well, without another lookup
+	cred.Passwd = "password1"
+	req, err := cred.buildRequest(context.Background(), "PUT", server.URL+"/source/home:testuser/_config", strings.NewReader("Prefer: foo"))
+	assert.NoError(t, err)
+	resp, err = cred.do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Prefer: foo", string(body))
+	assert.Equal(t, "password2", cred.Passwd)
+	assert.Equal(t, 3, lookups)
+
+	// no retry if the password was not read from the keyring
+	plain := &OSCCredentials{Name: "testuser", Passwd: "password1", Apiaddr: server.URL}
+	resp, err = plain.apiGetRequest(context.Background(), "about", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 3, lookups)
+}
+
+func TestGetCredentialsRefusesInternalHosts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Chdir(home)
+	t.Setenv("OSC_MCP_ALLOW_INTERNAL", "1")
+	viper.Set("user", "testuser")
+	viper.Set("password", "testpassword")
+	defer viper.Reset()
+
+	for _, api := range []string{"https://api.suse.de", "https://api.suse.cz"} {
+		viper.Set("api", api)
+		_, err := GetCredentials()
+		assert.Error(t, err, api)
+	}
+}
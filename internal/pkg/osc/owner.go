@@ -0,0 +1,92 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetOwnerParam struct {
+	Binary      string `json:"binary,omitempty" jsonschema:"Name of a binary package to find the owners for, e.g. libfoo1"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Name of a bundle to find the owners for. Either binary or package_name must be set."`
+	ProjectName string `json:"project_name,omitempty" jsonschema:"Project in which the owners are searched. Defaults to the project configured on the server, e.g. openSUSE:Factory."`
+}
+
+type Owner struct {
+	ProjectName string   `json:"project_name"`
+	PackageName string   `json:"package_name,omitempty" jsonschema:"Empty if the owners are the ones of the project"`
+	Maintainers []string `json:"maintainers"`
+	Bugowners   []string `json:"bugowners,omitempty"`
+}
+
+type GetOwnerResult struct {
+	Owners []Owner `json:"owners" jsonschema:"Owners of the package, groups are prefixed with group:. Submit changes to the project of the owners."`
+}
+
+// parseOwners reads the owners of a search/owner answer.
+func parseOwners(doc *etree.Document) []Owner {
+	owners := []Owner{}
+	collection := doc.SelectElement("collection")
+	if collection == nil {
+		return owners
+	}
+	for _, element := range collection.SelectElements("owner") {
+		owner := Owner{
+			ProjectName: element.SelectAttrValue("project", ""),
+			PackageName: element.SelectAttrValue("package", ""),
+			Maintainers: []string{},
+		}
+		add := func(role, name string) {
+			switch role {
+			case "maintainer":
+				owner.Maintainers = append(owner.Maintainers, name)
+			case "bugowner":
+				owner.Bugowners = append(owner.Bugowners, name)
+			}
+		}
+		for _, person := range element.SelectElements("person") {
+			add(person.SelectAttrValue("role", ""), person.SelectAttrValue("name", ""))
+		}
+		for _, group := range element.SelectElements("group") {
+			add(group.SelectAttrValue("role", ""), "group:"+group.SelectAttrValue("name", ""))
+		}
+		owners = append(owners, owner)
+	}
+	return owners
+}
+
+func (cred *OSCCredentials) GetOwner(ctx context.Context, req *mcp.CallToolRequest, params GetOwnerParam) (*mcp.CallToolResult, *GetOwnerResult, error) {
+	slog.Debug("mcp tool call: GetOwner", "params", params)
+	if (params.Binary == "") == (params.PackageName == "") {
+		return nil, nil, fmt.Errorf("either binary or package_name must be set")
+	}
+
+	queryParams := url.Values{}
+	if params.Binary != "" {
+		queryParams.Set("binary", params.Binary)
+	} else {
+		queryParams.Set("package", params.PackageName)
+	}
+	if params.ProjectName != "" {
+		queryParams.Set("project", params.ProjectName)
+	}
+	resp, err := cred.apiGetRequest(ctx, "search/owner?"+queryParams.Encode(), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return nil, &GetOwnerResult{Owners: parseOwners(doc)}, nil
+}
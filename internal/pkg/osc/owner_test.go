@@ -0,0 +1,39 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOwner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search/owner", r.URL.Path)
+		assert.Equal(t, "libfoo1", r.URL.Query().Get("binary"))
+		fmt.Fprint(w, `<collection>
+  <owner rootproject="openSUSE:Factory" project="devel:libraries" package="libfoo">
+    <person name="alice" role="maintainer"/>
+    <person name="bob" role="bugowner"/>
+    <group name="foo-team" role="maintainer"/>
+  </owner>
+</collection>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.GetOwner(context.Background(), nil, GetOwnerParam{Binary: "libfoo1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Owner{{
+		ProjectName: "devel:libraries",
+		PackageName: "libfoo",
+		Maintainers: []string{"alice", "group:foo-team"},
+		Bugowners:   []string{"bob"},
+	}}, result.Owners)
+
+	_, _, err = cred.GetOwner(context.Background(), nil, GetOwnerParam{Binary: "libfoo1", PackageName: "libfoo"})
+	assert.Error(t, err)
+}
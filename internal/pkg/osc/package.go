@@ -100,7 +100,10 @@ func (cred OSCCredentials) Create(ctx context.Context, req *mcp.CallToolRequest,
 		return nil, nil, err
 	}
 
-	_, err = cred.getProjectMetaInternal(ctx, projectName)
+	meta, err := cred.getProjectMetaInternal(ctx, projectName)
+	if err == nil && meta.ScmSync != "" {
+		return nil, nil, fmt.Errorf("can't create a bundle in project %s: %w: %s", projectName, ErrScmSync, meta.ScmSync)
+	}
 	if errors.Is(err, ErrBundleOrProjectNotFound) {
 		title := params.Title
 		if title == "" {
@@ -129,7 +132,7 @@ func (cred OSCCredentials) Create(ctx context.Context, req *mcp.CallToolRequest,
 	// now check if bundle allreay exists
 	if _, err := os.Stat(filepath.Join(cred.TempDir, projectName, params.PackageName)); err != nil {
 
-		if bundles, err := cred.searchRemoteSrcBundle(ctx, params.PackageName, []string{projectName}); err != nil {
+		if bundles, err := cred.searchPackageMatch(ctx, srcBundleMatch(SearchSrcBundleParam{Name: params.PackageName, Projects: []string{projectName}})); err != nil {
 			return nil, nil, err
 		} else if len(bundles) > 0 {
 			return nil, nil, fmt.Errorf("Bundle %s allreay exists in project %s", params.PackageName, projectName)
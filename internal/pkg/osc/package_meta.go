@@ -0,0 +1,201 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetPackageMetaParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle"`
+}
+
+type RepositoryFlags struct {
+	Repository  string `json:"repository" jsonschema:"Name of the repository. Empty sets the flags for all repositories."`
+	Build       *bool  `json:"build,omitempty" jsonschema:"Build the bundle in this repository"`
+	Publish     *bool  `json:"publish,omitempty" jsonschema:"Publish the built packages of this repository"`
+	UseForBuild *bool  `json:"useforbuild,omitempty" jsonschema:"Use the built packages for the builds of other packages"`
+}
+
+type PackageMeta struct {
+	ProjectName  string            `json:"project_name"`
+	PackageName  string            `json:"package_name"`
+	Title        string            `json:"title,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	DevelProject string            `json:"devel_project,omitempty" jsonschema:"Project in which the bundle is developed"`
+	DevelPackage string            `json:"devel_package,omitempty" jsonschema:"Name of the bundle in the devel project, if it differs"`
+	ScmSync      string            `json:"scmsync,omitempty" jsonschema:"Git URL from which the sources of the bundle are synchronized, e.g. https://src.opensuse.org/pool/foo#factory"`
+	Flags        []RepositoryFlags `json:"flags,omitempty" jsonschema:"Build, publish and useforbuild flags of the repositories. When reading, the state of every repository of the project is returned, when setting only the given flags are changed."`
+}
+
+// packageFlagTags are the flags of the package meta which are handled by
+// PackageMeta
+var packageFlagTags = []string{"build", "publish", "useforbuild"}
+
+func (cred *OSCCredentials) getPackageMetaInternal(ctx context.Context, projectName, packageName string) (*PackageMeta, error) {
+	if projectName == "" || packageName == "" {
+		return nil, fmt.Errorf("project and package name cannot be empty")
+	}
+	var projectDoc, packageDoc *etree.Document
+	errs := runParallel(cred.Concurrency,
+		func() (err error) {
+			projectDoc, err = cred.getMetaDoc(ctx, projectName, "")
+			return err
+		},
+		func() (err error) {
+			packageDoc, err = cred.getMetaDoc(ctx, projectName, packageName)
+			return err
+		},
+	)
+	if errs[0] != nil {
+		return nil, fmt.Errorf("failed to get meta of project %s: %w", projectName, errs[0])
+	}
+	if errs[1] != nil {
+		return nil, fmt.Errorf("failed to get meta of package %s/%s: %w", projectName, packageName, errs[1])
+	}
+	project := projectDoc.SelectElement("project")
+	pkg := packageDoc.SelectElement("package")
+	if project == nil || pkg == nil {
+		return nil, fmt.Errorf("package not found, name was: %s/%s", projectName, packageName)
+	}
+
+	meta := &PackageMeta{
+		ProjectName: projectName,
+		PackageName: packageName,
+		Flags:       []RepositoryFlags{},
+	}
+	if title := pkg.SelectElement("title"); title != nil {
+		meta.Title = strings.TrimSpace(title.Text())
+	}
+	if description := pkg.SelectElement("description"); description != nil {
+		meta.Description = strings.TrimSpace(description.Text())
+	}
+	if devel := pkg.SelectElement("devel"); devel != nil {
+		meta.DevelProject = devel.SelectAttrValue("project", "")
+		meta.DevelPackage = devel.SelectAttrValue("package", "")
+	}
+	if scmsync := pkg.SelectElement("scmsync"); scmsync != nil {
+		meta.ScmSync = strings.TrimSpace(scmsync.Text())
+	}
+	for _, repo := range project.SelectElements("repository") {
+		name := repo.SelectAttrValue("name", "")
+		states := make([]bool, len(packageFlagTags))
+		for i, tag := range packageFlagTags {
+			states[i] = flagState(pkg.SelectElement(tag), name, flagState(project.SelectElement(tag), name, true))
+		}
+		meta.Flags = append(meta.Flags, RepositoryFlags{
+			Repository:  name,
+			Build:       &states[0],
+			Publish:     &states[1],
+			UseForBuild: &states[2],
+		})
+	}
+	return meta, nil
+}
+
+// setPackageMetaInternal changes the given fields of the package meta and
+// keeps everything else, like the maintainers or scmsync. The package is
+// created if it doesn't exist.
+func (cred *OSCCredentials) setPackageMetaInternal(ctx context.Context, params PackageMeta) error {
+	doc, err := cred.getMetaDoc(ctx, params.ProjectName, params.PackageName)
+	if errors.Is(err, ErrBundleOrProjectNotFound) {
+		doc = etree.NewDocument()
+		pkg := doc.CreateElement("package")
+		pkg.CreateAttr("name", params.PackageName)
+		pkg.CreateAttr("project", params.ProjectName)
+		pkg.CreateElement("title")
+		pkg.CreateElement("description")
+	} else if err != nil {
+		return fmt.Errorf("failed to get meta of package %s/%s: %w", params.ProjectName, params.PackageName, err)
+	}
+	pkg := doc.SelectElement("package")
+	if pkg == nil {
+		return fmt.Errorf("empty meta for %s", metaPath(params.ProjectName, params.PackageName))
+	}
+
+	setText := func(tag, text string) {
+		if text == "" {
+			return
+		}
+		element := pkg.SelectElement(tag)
+		if element == nil {
+			element = pkg.CreateElement(tag)
+		}
+		element.SetText(text)
+	}
+	// insertAfter adds a new element behind the given tags, as the order of
+	// the elements is fixed in the schema
+	insertAfter := func(tag string, predecessors ...string) *etree.Element {
+		element := etree.NewElement(tag)
+		index := 0
+		for _, predecessor := range predecessors {
+			if other := pkg.SelectElement(predecessor); other != nil && other.Index()+1 > index {
+				index = other.Index() + 1
+			}
+		}
+		pkg.InsertChildAt(index, element)
+		return element
+	}
+	setText("title", params.Title)
+	setText("description", params.Description)
+	if params.DevelProject != "" {
+		devel := pkg.SelectElement("devel")
+		if devel == nil {
+			devel = insertAfter("devel", "title", "description")
+		}
+		devel.CreateAttr("project", params.DevelProject)
+		if params.DevelPackage != "" {
+			devel.CreateAttr("package", params.DevelPackage)
+		} else {
+			devel.RemoveAttr("package")
+		}
+	}
+	if params.ScmSync != "" {
+		scmsync := pkg.SelectElement("scmsync")
+		if scmsync == nil {
+			scmsync = insertAfter("scmsync", "title", "description", "devel", "releasename", "bcntsynctag")
+		}
+		scmsync.SetText(params.ScmSync)
+	}
+	for _, flags := range params.Flags {
+		for i, state := range []*bool{flags.Build, flags.Publish, flags.UseForBuild} {
+			if state != nil {
+				setFlag(pkg, packageFlagTags[i], flags.Repository, *state)
+			}
+		}
+	}
+	return cred.putMetaDoc(ctx, params.ProjectName, params.PackageName, doc)
+}
+
+func (cred *OSCCredentials) GetPackageMeta(ctx context.Context, req *mcp.CallToolRequest, params GetPackageMetaParam) (*mcp.CallToolResult, *PackageMeta, error) {
+	slog.Debug("mcp tool call: GetPackageMeta", "params", params)
+	meta, err := cred.getPackageMetaInternal(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, meta, nil
+}
+
+func (cred *OSCCredentials) SetPackageMeta(ctx context.Context, req *mcp.CallToolRequest, params PackageMeta) (*mcp.CallToolResult, *PackageMeta, error) {
+	slog.Debug("mcp tool call: SetPackageMeta", "params", params)
+	if params.ProjectName == "" || params.PackageName == "" {
+		return nil, nil, fmt.Errorf("project and package name cannot be empty")
+	}
+	if params.DevelPackage != "" && params.DevelProject == "" {
+		return nil, nil, fmt.Errorf("devel_package needs devel_project")
+	}
+	if err := cred.setPackageMetaInternal(ctx, params); err != nil {
+		return nil, nil, err
+	}
+	meta, err := cred.getPackageMetaInternal(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, meta, nil
+}
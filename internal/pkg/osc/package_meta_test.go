@@ -0,0 +1,108 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageMeta(t *testing.T) {
+	projectMeta := `<project name="home:testuser">
+  <title>test</title>
+  <description/>
+  <publish>
+    <disable repository="15.6"/>
+  </publish>
+  <repository name="openSUSE_Tumbleweed">
+    <arch>x86_64</arch>
+  </repository>
+  <repository name="15.6">
+    <arch>x86_64</arch>
+  </repository>
+</project>`
+	packageMeta := `<package name="foo" project="home:testuser">
+  <title>Foo</title>
+  <description>The foo tool</description>
+  <person userid="testuser" role="maintainer"/>
+  <build>
+    <disable repository="openSUSE_Tumbleweed"/>
+  </build>
+</package>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/_meta":
+			io.WriteString(w, projectMeta)
+		case "/source/home:testuser/foo/_meta":
+			if r.Method == "PUT" {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				packageMeta = string(body)
+			}
+			io.WriteString(w, packageMeta)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	yes, no := true, false
+
+	_, meta, err := cred.GetPackageMeta(context.Background(), &mcp.CallToolRequest{}, GetPackageMetaParam{ProjectName: "home:testuser", PackageName: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo", meta.Title)
+	assert.Equal(t, "The foo tool", meta.Description)
+	assert.Equal(t, []RepositoryFlags{
+		{Repository: "openSUSE_Tumbleweed", Build: &no, Publish: &yes, UseForBuild: &yes},
+		{Repository: "15.6", Build: &yes, Publish: &no, UseForBuild: &yes},
+	}, meta.Flags)
+
+	_, meta, err = cred.SetPackageMeta(context.Background(), &mcp.CallToolRequest{}, PackageMeta{
+		ProjectName:  "home:testuser",
+		PackageName:  "foo",
+		DevelProject: "devel:tools",
+		Flags: []RepositoryFlags{
+			{Repository: "openSUSE_Tumbleweed", Build: &yes},
+			{Repository: "15.6", UseForBuild: &no},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo", meta.Title)
+	assert.Equal(t, "devel:tools", meta.DevelProject)
+	assert.Equal(t, []RepositoryFlags{
+		{Repository: "openSUSE_Tumbleweed", Build: &yes, Publish: &yes, UseForBuild: &yes},
+		{Repository: "15.6", Build: &yes, Publish: &no, UseForBuild: &no},
+	}, meta.Flags)
+	assert.Contains(t, packageMeta, `<person userid="testuser" role="maintainer"/>`)
+	assert.Less(t, strings.Index(packageMeta, "<description>"), strings.Index(packageMeta, "<devel"))
+	assert.Less(t, strings.Index(packageMeta, "<devel"), strings.Index(packageMeta, "<person"))
+	assert.Less(t, strings.Index(packageMeta, "<build>"), strings.Index(packageMeta, "<useforbuild>"))
+	assert.Empty(t, meta.ScmSync)
+
+	_, meta, err = cred.SetPackageMeta(context.Background(), &mcp.CallToolRequest{}, PackageMeta{
+		ProjectName: "home:testuser",
+		PackageName: "foo",
+		ScmSync:     "https://src.opensuse.org/pool/foo#factory",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://src.opensuse.org/pool/foo#factory", meta.ScmSync)
+	assert.Equal(t, "devel:tools", meta.DevelProject)
+	assert.Less(t, strings.Index(packageMeta, "<devel"), strings.Index(packageMeta, "<scmsync>"))
+	assert.Less(t, strings.Index(packageMeta, "<scmsync>"), strings.Index(packageMeta, "<person"))
+
+	// a set without scmsync keeps it
+	_, meta, err = cred.SetPackageMeta(context.Background(), &mcp.CallToolRequest{}, PackageMeta{
+		ProjectName: "home:testuser",
+		PackageName: "foo",
+		Title:       "Foo tool",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo tool", meta.Title)
+	assert.Equal(t, "https://src.opensuse.org/pool/foo#factory", meta.ScmSync)
+}
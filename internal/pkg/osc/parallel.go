@@ -0,0 +1,27 @@
+package osc
+
+import "sync"
+
+const defaultConcurrency = 4
+
+// runParallel runs the tasks with at most limit of them at the same time and
+// returns their errors in the order of the tasks.
+func runParallel(limit int, tasks ...func() error) []error {
+	if limit <= 0 {
+		limit = defaultConcurrency
+	}
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = task()
+		}()
+	}
+	wg.Wait()
+	return errs
+}
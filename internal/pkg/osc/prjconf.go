@@ -0,0 +1,110 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetProjectConfigParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+}
+
+type ProjectConfigResult struct {
+	ProjectName string `json:"project_name"`
+	Config      string `json:"config" jsonschema:"Raw build configuration (prjconf) of the project"`
+}
+
+type SetProjectConfigParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	Config      string `json:"config" jsonschema:"Complete build configuration which replaces the existing one, e.g. with a Macros: section or Prefer: and Substitute: lines."`
+	Comment     string `json:"comment,omitempty" jsonschema:"Comment of the change"`
+}
+
+// projectConfigPath returns the api path of the build configuration of a
+// project.
+func projectConfigPath(projectName string) string {
+	return fmt.Sprintf("source/%s/_config", projectName)
+}
+
+func (cred *OSCCredentials) getProjectConfigInternal(ctx context.Context, projectName string) (string, error) {
+	resp, err := cred.apiGetRequest(ctx, projectConfigPath(projectName), map[string]string{"Accept": "text/plain"})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return string(body), nil
+}
+
+func (cred *OSCCredentials) GetProjectConfig(ctx context.Context, req *mcp.CallToolRequest, params GetProjectConfigParam) (*mcp.CallToolResult, *ProjectConfigResult, error) {
+	slog.Debug("mcp tool call: GetProjectConfig", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	config, err := cred.getProjectConfigInternal(ctx, params.ProjectName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &ProjectConfigResult{ProjectName: params.ProjectName, Config: config}, nil
+}
+
+func (cred *OSCCredentials) SetProjectConfig(ctx context.Context, req *mcp.CallToolRequest, params SetProjectConfigParam) (*mcp.CallToolResult, *ProjectConfigResult, error) {
+	slog.Debug("mcp tool call: SetProjectConfig", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if cred.Token == "" && (cred.Name == "" || cred.password() == "") {
+		return nil, nil, ErrNoUserOrPassword
+	}
+
+	apiURL := fmt.Sprintf("%s/%s", cred.GetAPiAddr(), projectConfigPath(params.ProjectName))
+	if params.Comment != "" {
+		apiURL += "?comment=" + url.QueryEscape(params.Comment)
+	}
+	httpReq, err := cred.buildRequest(ctx, "PUT", apiURL, strings.NewReader(params.Config))
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	_, summary := statusSummary(body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return nil, nil, fmt.Errorf("no permission to set the config of %s: %s", params.ProjectName, summary)
+	case http.StatusNotFound:
+		return nil, nil, fmt.Errorf("%w: %s", ErrBundleOrProjectNotFound, summary)
+	default:
+		return nil, nil, fmt.Errorf("api request failed with status: %s\nbody:\n%s", resp.Status, string(body))
+	}
+
+	config, err := cred.getProjectConfigInternal(ctx, params.ProjectName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &ProjectConfigResult{ProjectName: params.ProjectName, Config: config}, nil
+}
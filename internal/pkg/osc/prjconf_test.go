@@ -0,0 +1,58 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectConfig(t *testing.T) {
+	prjconf := "Prefer: libfoo1\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/_config":
+			if r.Method == "PUT" {
+				assert.Equal(t, "add macros", r.URL.Query().Get("comment"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				prjconf = string(body)
+				io.WriteString(w, `<status code="ok"><summary>Ok</summary></status>`)
+				return
+			}
+			io.WriteString(w, prjconf)
+		case "/source/home:other/_config":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<status code="change_project_no_permission"><summary>no permission to change project</summary></status>`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+
+	_, result, err := cred.GetProjectConfig(context.Background(), &mcp.CallToolRequest{}, GetProjectConfigParam{ProjectName: "home:testuser"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Prefer: libfoo1\n", result.Config)
+
+	newConfig := "Prefer: libfoo1\n\nMacros:\n%_without_tests 1\n:Macros\n"
+	_, result, err = cred.SetProjectConfig(context.Background(), &mcp.CallToolRequest{}, SetProjectConfigParam{
+		ProjectName: "home:testuser",
+		Config:      newConfig,
+		Comment:     "add macros",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, newConfig, result.Config)
+
+	_, _, err = cred.SetProjectConfig(context.Background(), &mcp.CallToolRequest{}, SetProjectConfigParam{ProjectName: "home:other", Config: newConfig})
+	assert.ErrorContains(t, err, "no permission to change project")
+
+	noCred := &OSCCredentials{Apiaddr: server.URL}
+	_, _, err = noCred.SetProjectConfig(context.Background(), &mcp.CallToolRequest{}, SetProjectConfigParam{ProjectName: "home:testuser", Config: newConfig})
+	assert.ErrorIs(t, err, ErrNoUserOrPassword)
+}
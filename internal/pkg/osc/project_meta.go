@@ -2,12 +2,14 @@ package osc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/beevik/etree"
@@ -15,10 +17,16 @@ import (
 )
 
 type GetProjectMetaParam struct {
-	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
-	Filter      string `json:"filter,omitempty" jsonschema:"Optional regexp to filter packages, returning all if empty"`
+	ProjectName   string `json:"project_name" jsonschema:"Name of the project"`
+	Filter        string `json:"filter,omitempty" jsonschema:"Optional regexp to filter packages, returning all if empty"`
+	PackageOffset int    `json:"package_offset,omitempty" jsonschema:"Number of packages to skip, use it with package_limit to page through big projects."`
+	PackageLimit  int    `json:"package_limit,omitempty" jsonschema:"Maximum number of returned packages, defaults to 100. The build status is only looked up for the returned packages as it is expensive for big projects."`
 }
 
+// defaultPackageLimit is the number of packages returned by GetProjectMeta if
+// no limit is given
+const defaultPackageLimit = 100
+
 type Repository struct {
 	Name           string   `json:"name" yaml:"name"`
 	PathProject    string   `json:"path_project,omitempty" yaml:"path_project,omitempty"`
@@ -26,32 +34,136 @@ type Repository struct {
 	Arches         []string `json:"arches,omitempty" yaml:"arches,omitempty"`
 }
 
+type MetaPerson struct {
+	UserID string `json:"userid"`
+	Role   string `json:"role" jsonschema:"maintainer, bugowner, reviewer, downloader or reader"`
+}
+
+type MetaGroup struct {
+	GroupID string `json:"groupid"`
+	Role    string `json:"role" jsonschema:"maintainer, bugowner, reviewer, downloader or reader"`
+}
+
+type MetaFlag struct {
+	Flag       string `json:"flag" jsonschema:"build, publish, debuginfo or useforbuild"`
+	Enable     bool   `json:"enable"`
+	Repository string `json:"repository,omitempty" jsonschema:"Repository the entry applies to, all repositories if empty"`
+	Arch       string `json:"arch,omitempty" jsonschema:"Architecture the entry applies to, all architectures if empty"`
+}
+
+// parseMetaFlags reads the entries of the build, publish, debuginfo and
+// useforbuild flags of a meta root in their order.
+func parseMetaFlags(root *etree.Element) []MetaFlag {
+	var flags []MetaFlag
+	for _, tag := range flagOrder {
+		flag := root.SelectElement(tag)
+		if flag == nil {
+			continue
+		}
+		for _, entry := range flag.ChildElements() {
+			flags = append(flags, MetaFlag{
+				Flag:       tag,
+				Enable:     entry.Tag == "enable",
+				Repository: entry.SelectAttrValue("repository", ""),
+				Arch:       entry.SelectAttrValue("arch", ""),
+			})
+		}
+	}
+	return flags
+}
+
+// addMetaFlags adds the flag elements for flags to a meta root in the order
+// of the schema.
+func addMetaFlags(root *etree.Element, flags []MetaFlag) {
+	for _, tag := range flagOrder {
+		var flag *etree.Element
+		for _, f := range flags {
+			if f.Flag != tag {
+				continue
+			}
+			if flag == nil {
+				flag = root.CreateElement(tag)
+			}
+			state := "disable"
+			if f.Enable {
+				state = "enable"
+			}
+			entry := flag.CreateElement(state)
+			if f.Repository != "" {
+				entry.CreateAttr("repository", f.Repository)
+			}
+			if f.Arch != "" {
+				entry.CreateAttr("arch", f.Arch)
+			}
+		}
+	}
+}
+
 type Package struct {
 	Name   string            `json:"name"`
 	Status map[string]string `json:"status,omitempty"`
 }
 
 type ProjectMeta struct {
-	ProjectName  string       `json:"project_name"`
-	Title        string       `json:"title,omitempty"`
-	Description  string       `json:"description,omitempty"`
-	Maintainers  []string     `json:"maintainers,omitempty"`
-	Repositories []Repository `json:"repositories,omitempty"`
-	Packages     []*Package   `json:"packages,omitempty"`
-	SubProjects  []SubProject `json:"sub_projects,omitempty"`
-	NumPackages  int          `json:"num_packages,omitempty"`
-	NumFiltered  int          `json:"num_filtered,omitempty"`
+	ProjectName          string       `json:"project_name"`
+	Title                string       `json:"title,omitempty"`
+	Description          string       `json:"description,omitempty"`
+	ScmSync              string       `json:"scmsync,omitempty" jsonschema:"Git URL from which the sources of the project are synchronized."`
+	Maintainers          []string     `json:"maintainers,omitempty"`
+	Persons              []MetaPerson `json:"persons,omitempty" jsonschema:"Users with their roles, including the maintainers. When setting, the maintainers are also added and the existing persons with other roles than maintainer are kept if not set."`
+	Groups               []MetaGroup  `json:"groups,omitempty" jsonschema:"Groups with their roles. The existing groups are kept if not set."`
+	AccessDisabled       bool         `json:"access_disabled,omitempty" jsonschema:"Hide the project from everybody but its maintainers, for embargoed projects. Can only be set on creation and is never removed."`
+	SourceAccessDisabled bool         `json:"sourceaccess_disabled,omitempty" jsonschema:"Hide the sources of the project from everybody but its maintainers. Is never removed once set."`
+	Flags                []MetaFlag   `json:"flags,omitempty" jsonschema:"Entries of the build, publish, debuginfo and useforbuild flags. The existing flags are kept if not set."`
+	Repositories         []Repository `json:"repositories,omitempty"`
+	Packages             []*Package   `json:"packages,omitempty"`
+	SubProjects          []SubProject `json:"sub_projects,omitempty"`
+	NumPackages          int          `json:"num_packages,omitempty"`
+	NumFiltered          int          `json:"num_filtered,omitempty"`
 }
 
 type SubProject struct {
 	Name string `json:"name"`
 }
 
-func (cred *OSCCredentials) listProjectPackages(ctx context.Context, projectName string) ([]*Package, error) {
-	if projectName == "" {
-		return nil, fmt.Errorf("project name cannot be empty")
+// parseBuildStatus reads the status codes of a build result list. The status
+// of every package is keyed by repository/arch, with the flavor appended for
+// multibuild packages.
+func parseBuildStatus(resultList *etree.Element) map[string]map[string]string {
+	packages := make(map[string]map[string]string)
+	for _, result := range resultList.SelectElements("result") {
+		repo := result.SelectAttrValue("repository", "")
+		arch := result.SelectAttrValue("arch", "")
+		if repo == "" || arch == "" {
+			continue
+		}
+		repoArch := fmt.Sprintf("%s/%s", repo, arch)
+		for _, status := range result.SelectElements("status") {
+			pkgNameWithFlavor := status.SelectAttrValue("package", "")
+			code := status.SelectAttrValue("code", "")
+			pkgName := pkgNameWithFlavor
+			flavor := ""
+			if strings.Contains(pkgNameWithFlavor, ":") {
+				parts := strings.SplitN(pkgNameWithFlavor, ":", 2)
+				pkgName = parts[0]
+				flavor = parts[1]
+			}
+
+			if packages[pkgName] == nil {
+				packages[pkgName] = make(map[string]string)
+			}
+			key := repoArch
+			if flavor != "" {
+				key = fmt.Sprintf("%s/%s", repoArch, flavor)
+			}
+			packages[pkgName][key] = code
+		}
 	}
+	return packages
+}
 
+// getProjectPackageNames lists the names of the packages in a project.
+func (cred *OSCCredentials) getProjectPackageNames(ctx context.Context, projectName string) ([]string, error) {
 	apiURL, err := url.Parse(fmt.Sprintf("%s/source/%s", cred.GetAPiAddr(), projectName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse API URL: %w", err)
@@ -63,11 +175,10 @@ func (cred *OSCCredentials) listProjectPackages(ctx context.Context, projectName
 	}
 
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 	req.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -97,82 +208,85 @@ func (cred *OSCCredentials) listProjectPackages(ctx context.Context, projectName
 			}
 		}
 	}
+	return packageNames, nil
+}
 
-	packages := make([]*Package, len(packageNames))
-	packageMap := make(map[string]*Package)
-	for i, name := range packageNames {
-		packages[i] = &Package{Name: name}
-		packageMap[name] = packages[i]
-	}
-
-	// Get build results
+// getProjectBuildStatus reads the build status of the given packages of a
+// project, or of all packages if none are given.
+// Failures only result in a warning as the status is optional.
+func (cred *OSCCredentials) getProjectBuildStatus(ctx context.Context, projectName string, packageNames ...string) map[string]map[string]string {
 	buildResultURL, err := url.Parse(fmt.Sprintf("%s/build/%s/_result", cred.GetAPiAddr(), projectName))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse build result API URL: %w", err)
+		slog.Warn("failed to parse build result API URL", "project", projectName, "error", err)
+		return nil
+	}
+	if len(packageNames) > 0 {
+		buildResultURL.RawQuery = url.Values{"package": packageNames}.Encode()
 	}
 
-	req, err = http.NewRequestWithContext(ctx, "GET", buildResultURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", buildResultURL.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for build result: %w", err)
+		slog.Warn("failed to create request for build result", "project", projectName, "error", err)
+		return nil
 	}
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 	req.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	resp, err = client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request for build result: %w", err)
+		slog.Warn("failed to execute request for build result", "project", projectName, "error", err)
+		return nil
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		slog.Warn("failed to get build results", "project", projectName, "status", resp.Status)
-		return packages, nil
+		return nil
 	}
 
 	buildDoc := etree.NewDocument()
 	if _, err := buildDoc.ReadFrom(resp.Body); err != nil {
 		slog.Warn("failed to parse build result", "project", projectName, "error", err)
-		return packages, nil
+		return nil
 	}
 
 	resultList := buildDoc.SelectElement("resultlist")
 	if resultList == nil {
 		slog.Warn("no resultlist found in build result", "project", projectName)
-		return packages, nil
+		return nil
 	}
+	return parseBuildStatus(resultList)
+}
 
-	for _, result := range resultList.SelectElements("result") {
-		repo := result.SelectAttrValue("repository", "")
-		arch := result.SelectAttrValue("arch", "")
-		if repo == "" || arch == "" {
-			continue
-		}
-		repoArch := fmt.Sprintf("%s/%s", repo, arch)
-		for _, status := range result.SelectElements("status") {
-			pkgNameWithFlavor := status.SelectAttrValue("package", "")
-			code := status.SelectAttrValue("code", "")
-			pkgName := pkgNameWithFlavor
-			flavor := ""
-			if strings.Contains(pkgNameWithFlavor, ":") {
-				parts := strings.SplitN(pkgNameWithFlavor, ":", 2)
-				pkgName = parts[0]
-				flavor = parts[1]
-			}
+// listProjectPackages lists the packages of a project together with their
+// build status. The package list and the build results are fetched in
+// parallel.
+func (cred *OSCCredentials) listProjectPackages(ctx context.Context, projectName string) ([]*Package, error) {
+	if projectName == "" {
+		return nil, fmt.Errorf("project name cannot be empty")
+	}
 
-			if pkg, ok := packageMap[pkgName]; ok {
-				if pkg.Status == nil {
-					pkg.Status = make(map[string]string)
-				}
-				key := repoArch
-				if flavor != "" {
-					key = fmt.Sprintf("%s/%s", repoArch, flavor)
-				}
-				pkg.Status[key] = code
-			}
-		}
+	var packageNames []string
+	var buildStatus map[string]map[string]string
+	errs := runParallel(cred.Concurrency,
+		func() (err error) {
+			packageNames, err = cred.getProjectPackageNames(ctx, projectName)
+			return err
+		},
+		func() error {
+			buildStatus = cred.getProjectBuildStatus(ctx, projectName)
+			return nil
+		},
+	)
+	if errs[0] != nil {
+		return nil, errs[0]
 	}
 
+	packages := make([]*Package, len(packageNames))
+	for i, name := range packageNames {
+		packages[i] = &Package{Name: name, Status: buildStatus[name]}
+	}
 	return packages, nil
 }
 
@@ -192,11 +306,10 @@ func (cred *OSCCredentials) getProjectMetaInternal(ctx context.Context, projectN
 	}
 
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 	req.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -228,13 +341,29 @@ func (cred *OSCCredentials) getProjectMetaInternal(ctx context.Context, projectN
 	if description := projectElement.SelectElement("description"); description != nil {
 		meta.Description = description.Text()
 	}
+	if scmsync := projectElement.SelectElement("scmsync"); scmsync != nil {
+		meta.ScmSync = strings.TrimSpace(scmsync.Text())
+	}
 
 	for _, person := range projectElement.SelectElements("person") {
-		if role := person.SelectAttrValue("role", ""); role == "maintainer" {
-			meta.Maintainers = append(meta.Maintainers, person.SelectAttrValue("userid", ""))
+		userID := person.SelectAttrValue("userid", "")
+		role := person.SelectAttrValue("role", "")
+		if role == "maintainer" {
+			meta.Maintainers = append(meta.Maintainers, userID)
 		}
+		meta.Persons = append(meta.Persons, MetaPerson{UserID: userID, Role: role})
+	}
+	for _, group := range projectElement.SelectElements("group") {
+		meta.Groups = append(meta.Groups, MetaGroup{
+			GroupID: group.SelectAttrValue("groupid", ""),
+			Role:    group.SelectAttrValue("role", ""),
+		})
 	}
 
+	meta.AccessDisabled = !flagState(projectElement.SelectElement("access"), "", true)
+	meta.SourceAccessDisabled = !flagState(projectElement.SelectElement("sourceaccess"), "", true)
+	meta.Flags = parseMetaFlags(projectElement)
+
 	for _, repo := range projectElement.SelectElements("repository") {
 		r := Repository{
 			Name: repo.SelectAttrValue("name", ""),
@@ -264,11 +393,10 @@ func (cred *OSCCredentials) listAllProjects(ctx context.Context) ([]string, erro
 	}
 
 	req.Header.Set("User-Agent", "osc-mcp")
-	req.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(req)
 	req.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cred.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -319,40 +447,68 @@ func (cred *OSCCredentials) listSubProjects(ctx context.Context, projectName str
 
 func (cred *OSCCredentials) GetProjectMeta(ctx context.Context, req *mcp.CallToolRequest, params GetProjectMetaParam) (*mcp.CallToolResult, *ProjectMeta, error) {
 	slog.Debug("mcp tool call: GetProjectMeta", "params", params)
-	res, err := cred.getProjectMetaInternal(ctx, params.ProjectName)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	packages, err := cred.listProjectPackages(ctx, params.ProjectName)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to list packages for project %s: %w", params.ProjectName, err)
+	if params.PackageOffset < 0 {
+		return nil, nil, fmt.Errorf("package offset must not be negative")
 	}
-
-	res.NumPackages = len(packages)
-
+	var re *regexp.Regexp
 	if params.Filter != "" {
-		re, err := regexp.Compile(params.Filter)
-		if err != nil {
+		var err error
+		if re, err = regexp.Compile(params.Filter); err != nil {
 			return nil, nil, fmt.Errorf("invalid filter regexp: %w", err)
 		}
-		var filteredPackages []*Package
-		for _, pkg := range packages {
-			if re.MatchString(pkg.Name) {
-				filteredPackages = append(filteredPackages, pkg)
+	}
+	var res *ProjectMeta
+	var packageNames []string
+	var subProjects []SubProject
+	errs := runParallel(cred.Concurrency,
+		func() (err error) {
+			res, err = cred.getProjectMetaInternal(ctx, params.ProjectName)
+			return err
+		},
+		func() (err error) {
+			packageNames, err = cred.getProjectPackageNames(ctx, params.ProjectName)
+			return err
+		},
+		func() (err error) {
+			subProjects, err = cred.listSubProjects(ctx, params.ProjectName)
+			return err
+		},
+	)
+	if errs[0] != nil {
+		return nil, nil, errs[0]
+	}
+	if errs[1] != nil {
+		return nil, nil, fmt.Errorf("failed to list packages for project %s: %w", params.ProjectName, errs[1])
+	}
+
+	res.NumPackages = len(packageNames)
+	if re != nil {
+		var filtered []string
+		for _, name := range packageNames {
+			if re.MatchString(name) {
+				filtered = append(filtered, name)
 			}
 		}
-		res.Packages = filteredPackages
-		res.NumFiltered = len(filteredPackages)
-	} else {
-		if len(packages) <= 100 {
-			res.Packages = packages
+		packageNames = filtered
+		res.NumFiltered = len(filtered)
+	}
+
+	limit := params.PackageLimit
+	if limit <= 0 {
+		limit = defaultPackageLimit
+	}
+	offset := min(params.PackageOffset, len(packageNames))
+	packageNames = packageNames[offset:min(offset+limit, len(packageNames))]
+	if len(packageNames) > 0 {
+		buildStatus := cred.getProjectBuildStatus(ctx, params.ProjectName, packageNames...)
+		res.Packages = make([]*Package, len(packageNames))
+		for i, name := range packageNames {
+			res.Packages[i] = &Package{Name: name, Status: buildStatus[name]}
 		}
 	}
 
-	subProjects, err := cred.listSubProjects(ctx, params.ProjectName)
-	if err != nil {
-		slog.Warn("failed to list subprojects", "project", params.ProjectName, "error", err)
+	if errs[2] != nil {
+		slog.Warn("failed to list subprojects", "project", params.ProjectName, "error", errs[2])
 	} else {
 		res.SubProjects = subProjects
 	}
@@ -372,11 +528,41 @@ func (cred *OSCCredentials) setProjectMetaInternal(ctx context.Context, params P
 	if params.Description != "" {
 		project.CreateElement("description").SetText(params.Description)
 	}
+	if params.ScmSync != "" {
+		project.CreateElement("scmsync").SetText(params.ScmSync)
+	}
 
+	persons := []MetaPerson{}
 	for _, maintainer := range params.Maintainers {
+		persons = append(persons, MetaPerson{UserID: maintainer, Role: "maintainer"})
+	}
+	seenPersons := map[MetaPerson]bool{}
+	for _, p := range append(persons, params.Persons...) {
+		if seenPersons[p] {
+			continue
+		}
+		seenPersons[p] = true
 		person := project.CreateElement("person")
-		person.CreateAttr("userid", maintainer)
-		person.CreateAttr("role", "maintainer")
+		person.CreateAttr("userid", p.UserID)
+		person.CreateAttr("role", p.Role)
+	}
+	seenGroups := map[MetaGroup]bool{}
+	for _, g := range params.Groups {
+		if seenGroups[g] {
+			continue
+		}
+		seenGroups[g] = true
+		group := project.CreateElement("group")
+		group.CreateAttr("groupid", g.GroupID)
+		group.CreateAttr("role", g.Role)
+	}
+
+	addMetaFlags(project, params.Flags)
+	if params.SourceAccessDisabled {
+		project.CreateElement("sourceaccess").CreateElement("disable")
+	}
+	if params.AccessDisabled {
+		project.CreateElement("access").CreateElement("disable")
 	}
 
 	for _, repo := range params.Repositories {
@@ -411,12 +597,11 @@ func (cred *OSCCredentials) setProjectMetaInternal(ctx context.Context, params P
 	}
 
 	httpReq.Header.Set("User-Agent", "osc-mcp")
-	httpReq.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(httpReq)
 	httpReq.Header.Set("Content-Type", "application/xml; charset=utf-8")
 	httpReq.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := cred.do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -434,6 +619,11 @@ func (cred *OSCCredentials) SetProjectMeta(ctx context.Context, req *mcp.CallToo
 	if params.ProjectName == "" {
 		return nil, nil, fmt.Errorf("project name cannot be empty")
 	}
+	for _, flag := range params.Flags {
+		if !slices.Contains(flagOrder, flag.Flag) {
+			return nil, nil, fmt.Errorf("unknown flag '%s', valid flags are: %s", flag.Flag, strings.Join(flagOrder, ", "))
+		}
+	}
 	if len(params.Repositories) == 0 {
 		params.Repositories = []Repository{
 			{
@@ -444,6 +634,31 @@ func (cred *OSCCredentials) SetProjectMeta(ctx context.Context, req *mcp.CallToo
 		}
 	}
 
+	// never open up a hidden project by leaving out its access flags
+	existing, err := cred.getProjectMetaInternal(ctx, params.ProjectName)
+	if err == nil {
+		params.AccessDisabled = params.AccessDisabled || existing.AccessDisabled
+		params.SourceAccessDisabled = params.SourceAccessDisabled || existing.SourceAccessDisabled
+		if params.Flags == nil {
+			params.Flags = existing.Flags
+		}
+		if params.Persons == nil {
+			for _, person := range existing.Persons {
+				if person.Role != "maintainer" {
+					params.Persons = append(params.Persons, person)
+				}
+			}
+		}
+		if params.Groups == nil {
+			params.Groups = existing.Groups
+		}
+		if params.ScmSync == "" {
+			params.ScmSync = existing.ScmSync
+		}
+	} else if !errors.Is(err, ErrBundleOrProjectNotFound) {
+		return nil, nil, fmt.Errorf("failed to read existing meta of project %s: %w", params.ProjectName, err)
+	}
+
 	if err := cred.setProjectMetaInternal(ctx, params); err != nil {
 		return nil, nil, err
 	}
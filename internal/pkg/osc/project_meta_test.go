@@ -0,0 +1,274 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newProjectServer serves a project with two packages and counts the maximal
+// number of requests in flight at the same time.
+func newProjectServer(t *testing.T, maxInFlight *int) *httptest.Server {
+	var mu sync.Mutex
+	inFlight := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		*maxInFlight = max(*maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/source/home:testuser/_meta":
+			fmt.Fprint(w, `<project name="home:testuser"><title>Test</title><description/></project>`)
+		case "/source/home:testuser":
+			fmt.Fprint(w, `<directory><entry name="bar"/><entry name="foo"/></directory>`)
+		case "/build/home:testuser/_result":
+			fmt.Fprint(w, `<resultlist><result repository="openSUSE_Tumbleweed" arch="x86_64"><status package="foo" code="succeeded"/></result></resultlist>`)
+		case "/source":
+			fmt.Fprint(w, `<directory><entry name="home:testuser"/><entry name="home:testuser:sub"/></directory>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetProjectMetaConcurrency(t *testing.T) {
+	for _, concurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			maxInFlight := 0
+			server := newProjectServer(t, &maxInFlight)
+			defer server.Close()
+
+			cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL, Concurrency: concurrency}
+			_, meta, err := cred.GetProjectMeta(context.Background(), nil, GetProjectMetaParam{ProjectName: "home:testuser"})
+			assert.NoError(t, err)
+			assert.Equal(t, "Test", meta.Title)
+			assert.Equal(t, []*Package{
+				{Name: "bar"},
+				{Name: "foo", Status: map[string]string{"openSUSE_Tumbleweed/x86_64": "succeeded"}},
+			}, meta.Packages)
+			assert.Equal(t, []SubProject{{Name: "sub"}}, meta.SubProjects)
+			if concurrency == 1 {
+				assert.Equal(t, 1, maxInFlight)
+			} else {
+				assert.Greater(t, maxInFlight, 1)
+			}
+		})
+	}
+}
+
+func TestSetProjectMetaKeepsAccessFlags(t *testing.T) {
+	var mu sync.Mutex
+	meta := `<project name="home:testuser:embargo"><title/><description/><access><disable/></access></project>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/source/home:testuser:embargo/_meta", r.URL.Path)
+		if r.Method == "PUT" {
+			body, _ := io.ReadAll(r.Body)
+			meta = string(body)
+		}
+		fmt.Fprint(w, meta)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.SetProjectMeta(context.Background(), nil, ProjectMeta{
+		ProjectName:          "home:testuser:embargo",
+		Title:                "Embargoed",
+		SourceAccessDisabled: true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.AccessDisabled)
+	assert.True(t, result.SourceAccessDisabled)
+	assert.Contains(t, meta, "<access>")
+	assert.Contains(t, meta, "<sourceaccess>")
+}
+
+func TestSetProjectMetaKeepsFlags(t *testing.T) {
+	var mu sync.Mutex
+	meta := `<project name="home:testuser:flags">
+  <title>Flags</title>
+  <description/>
+  <build>
+    <disable/>
+    <enable repository="openSUSE_Tumbleweed" arch="x86_64"/>
+  </build>
+  <debuginfo>
+    <enable/>
+  </debuginfo>
+  <repository name="openSUSE_Tumbleweed">
+    <path project="openSUSE:Factory" repository="snapshot"/>
+    <arch>x86_64</arch>
+  </repository>
+</project>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "PUT" {
+			body, _ := io.ReadAll(r.Body)
+			meta = string(body)
+		}
+		fmt.Fprint(w, meta)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	existing, err := cred.getProjectMetaInternal(context.Background(), "home:testuser:flags")
+	assert.NoError(t, err)
+	expected := []MetaFlag{
+		{Flag: "build", Enable: false},
+		{Flag: "build", Enable: true, Repository: "openSUSE_Tumbleweed", Arch: "x86_64"},
+		{Flag: "debuginfo", Enable: true},
+	}
+	assert.Equal(t, expected, existing.Flags)
+
+	_, result, err := cred.SetProjectMeta(context.Background(), nil, *existing)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result.Flags)
+	assert.Contains(t, meta, "<build>\n    <disable/>")
+	assert.Less(t, strings.Index(meta, "<debuginfo>"), strings.Index(meta, "<repository"))
+
+	// flags which are left out are kept
+	_, result, err = cred.SetProjectMeta(context.Background(), nil, ProjectMeta{ProjectName: "home:testuser:flags", Repositories: existing.Repositories})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result.Flags)
+
+	_, _, err = cred.SetProjectMeta(context.Background(), nil, ProjectMeta{ProjectName: "home:testuser:flags", Flags: []MetaFlag{{Flag: "lock"}}})
+	assert.ErrorContains(t, err, "unknown flag")
+}
+
+func TestSetProjectMetaKeepsScmSync(t *testing.T) {
+	var mu sync.Mutex
+	meta := `<project name="home:testuser:git">
+  <title>Git</title>
+  <description/>
+  <scmsync>https://src.opensuse.org/testuser/project</scmsync>
+  <repository name="openSUSE_Tumbleweed">
+    <path project="openSUSE:Factory" repository="snapshot"/>
+    <arch>x86_64</arch>
+  </repository>
+</project>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "PUT" {
+			body, _ := io.ReadAll(r.Body)
+			meta = string(body)
+		}
+		fmt.Fprint(w, meta)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.SetProjectMeta(context.Background(), nil, ProjectMeta{ProjectName: "home:testuser:git", Title: "Git sources"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Git sources", result.Title)
+	assert.Equal(t, "https://src.opensuse.org/testuser/project", result.ScmSync)
+	assert.Contains(t, meta, "<scmsync>https://src.opensuse.org/testuser/project</scmsync>")
+
+	_, result, err = cred.SetProjectMeta(context.Background(), nil, ProjectMeta{ProjectName: "home:testuser:git", ScmSync: "https://src.opensuse.org/testuser/other"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://src.opensuse.org/testuser/other", result.ScmSync)
+}
+
+func TestSetProjectMetaKeepsRoles(t *testing.T) {
+	var mu sync.Mutex
+	meta := `<project name="home:testuser:roles">
+  <title>Roles</title>
+  <description/>
+  <person userid="testuser" role="maintainer"/>
+  <person userid="bugs" role="bugowner"/>
+  <person userid="testuser" role="reviewer"/>
+  <group groupid="factory-maintainers" role="maintainer"/>
+</project>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "PUT" {
+			body, _ := io.ReadAll(r.Body)
+			meta = string(body)
+		}
+		fmt.Fprint(w, meta)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	existing, err := cred.getProjectMetaInternal(context.Background(), "home:testuser:roles")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testuser"}, existing.Maintainers)
+	assert.Equal(t, []MetaPerson{{"testuser", "maintainer"}, {"bugs", "bugowner"}, {"testuser", "reviewer"}}, existing.Persons)
+	assert.Equal(t, []MetaGroup{{"factory-maintainers", "maintainer"}}, existing.Groups)
+
+	_, result, err := cred.SetProjectMeta(context.Background(), nil, *existing)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.Persons, result.Persons)
+	assert.Equal(t, existing.Groups, result.Groups)
+	assert.Equal(t, 1, strings.Count(meta, `userid="testuser" role="maintainer"`))
+
+	// only changing the maintainers keeps the other roles
+	_, result, err = cred.SetProjectMeta(context.Background(), nil, ProjectMeta{ProjectName: "home:testuser:roles", Maintainers: []string{"other"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, result.Maintainers)
+	assert.Equal(t, []MetaPerson{{"other", "maintainer"}, {"bugs", "bugowner"}, {"testuser", "reviewer"}}, result.Persons)
+	assert.Equal(t, existing.Groups, result.Groups)
+}
+
+func TestGetProjectMetaPaging(t *testing.T) {
+	var mu sync.Mutex
+	var statusQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/_meta":
+			fmt.Fprint(w, `<project name="home:testuser"><title>Test</title><description/></project>`)
+		case "/source/home:testuser":
+			fmt.Fprint(w, `<directory>`)
+			for i := 0; i < 250; i++ {
+				fmt.Fprintf(w, `<entry name="pkg%03d"/>`, i)
+			}
+			fmt.Fprint(w, `</directory>`)
+		case "/build/home:testuser/_result":
+			mu.Lock()
+			statusQueries = append(statusQueries, r.URL.RawQuery)
+			mu.Unlock()
+			fmt.Fprint(w, `<resultlist><result repository="openSUSE_Tumbleweed" arch="x86_64"><status package="pkg200" code="failed"/></result></resultlist>`)
+		case "/source":
+			fmt.Fprint(w, `<directory><entry name="home:testuser"/></directory>`)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, meta, err := cred.GetProjectMeta(context.Background(), nil, GetProjectMetaParam{ProjectName: "home:testuser"})
+	assert.NoError(t, err)
+	assert.Equal(t, 250, meta.NumPackages)
+	assert.Len(t, meta.Packages, defaultPackageLimit)
+	assert.Equal(t, "pkg000", meta.Packages[0].Name)
+
+	_, meta, err = cred.GetProjectMeta(context.Background(), nil, GetProjectMetaParam{ProjectName: "home:testuser", PackageOffset: 200, PackageLimit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, meta.Packages, 10)
+	assert.Equal(t, "pkg200", meta.Packages[0].Name)
+	assert.Equal(t, map[string]string{"openSUSE_Tumbleweed/x86_64": "failed"}, meta.Packages[0].Status)
+	assert.Equal(t, 10, strings.Count(statusQueries[1], "package="))
+
+	_, meta, err = cred.GetProjectMeta(context.Background(), nil, GetProjectMetaParam{ProjectName: "home:testuser", Filter: "^pkg1", PackageLimit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, meta.NumFiltered)
+	assert.Len(t, meta.Packages, 20)
+}
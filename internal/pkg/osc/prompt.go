@@ -57,3 +57,21 @@ If a Source: line in a spec file matches the pattern Source: https://github.com/
 		},
 	}, nil
 }
+
+func (cred *OSCCredentials) PromptSubmitWorkflow(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	slog.Debug("PromptSubmitWorkflow was called")
+	return &mcp.GetPromptResult{
+		Description: "Lifecycle of a change from commit to an accepted submit request.",
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: `Changes to a package in another project are made in a branch and submitted back.
+1. Branch the package with branch_bundle, it is checked out to the home project "home:` + cred.Name + `".
+2. Modify and build the package locally, then use commit to upload the changes. The commit message becomes the changes entry.
+3. Check the remote build result of the branch before submitting.
+4. Use create_submit_request to submit the branched package back to its origin project. Describe why the change is needed.
+5. Reviews can be requested with add_review for a user, group or project.
+6. Pending reviews assigned to the user or one of its groups (see list_my_groups) are accepted or declined with set_review_state. Always give a comment when declining.
+Use list_requests and get_request to follow the state of the request.
+`}},
+		},
+	}, nil
+}
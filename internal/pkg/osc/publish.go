@@ -0,0 +1,190 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// flagState applies the enable/disable entries of a flag element like
+// <publish> to the state def of the given repository. Repository specific
+// entries take precedence over global ones, entries for a single architecture
+// are ignored.
+func flagState(flag *etree.Element, repository string, def bool) bool {
+	if flag == nil {
+		return def
+	}
+	state := def
+	for _, entry := range flag.ChildElements() {
+		if entry.SelectAttr("arch") != nil || entry.SelectAttr("repository") != nil {
+			continue
+		}
+		state = entry.Tag == "enable"
+	}
+	for _, entry := range flag.ChildElements() {
+		if entry.SelectAttr("arch") != nil || entry.SelectAttrValue("repository", "") != repository {
+			continue
+		}
+		state = entry.Tag == "enable"
+	}
+	return state
+}
+
+// flagOrder is the order of the flag elements in the project and package meta.
+var flagOrder = []string{"build", "publish", "debuginfo", "useforbuild"}
+
+// createFlagElement creates a flag element like <publish> in the meta root at
+// the position required by the schema.
+func createFlagElement(root *etree.Element, tag string) *etree.Element {
+	flag := etree.NewElement(tag)
+	after := false
+	for _, name := range flagOrder {
+		if name == tag {
+			after = true
+			continue
+		}
+		if !after {
+			continue
+		}
+		if successor := root.SelectElement(name); successor != nil {
+			root.InsertChildAt(successor.Index(), flag)
+			return flag
+		}
+	}
+	for _, name := range []string{"binarydownload", "sourceaccess", "access", "lock", "url", "bcntsynctag", "repository"} {
+		if successor := root.SelectElement(name); successor != nil {
+			root.InsertChildAt(successor.Index(), flag)
+			return flag
+		}
+	}
+	root.AddChild(flag)
+	return flag
+}
+
+// setFlag enables or disables a flag like publish in the meta root for a
+// repository, or for all repositories if repository is empty. The entries
+// which are replaced by the new one are dropped.
+func setFlag(root *etree.Element, tag, repository string, enable bool) {
+	flag := root.SelectElement(tag)
+	if flag == nil {
+		flag = createFlagElement(root, tag)
+	}
+	for _, entry := range flag.ChildElements() {
+		if entry.SelectAttr("arch") != nil {
+			continue
+		}
+		if repository == "" || entry.SelectAttrValue("repository", "") == repository {
+			flag.RemoveChild(entry)
+		}
+	}
+	state := "disable"
+	if enable {
+		state = "enable"
+	}
+	entry := flag.CreateElement(state)
+	if repository != "" {
+		entry.CreateAttr("repository", repository)
+	}
+}
+
+type GetPublishStateParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Name of the bundle. If empty, the publish state of the project is returned."`
+}
+
+type SetPublishStateParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Name of the bundle. If empty, the publish flag of the project is set."`
+	Repository  string `json:"repository,omitempty" jsonschema:"Repository to set the publish flag for. If empty, the flag is set for all repositories."`
+	Enable      bool   `json:"enable" jsonschema:"Enable or disable publishing."`
+}
+
+type RepositoryPublishState struct {
+	Repository string `json:"repository"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type PublishStateResult struct {
+	ProjectName  string                   `json:"project_name"`
+	PackageName  string                   `json:"package_name,omitempty"`
+	Repositories []RepositoryPublishState `json:"repositories"`
+}
+
+func (cred *OSCCredentials) getPublishState(ctx context.Context, projectName, packageName string) (*PublishStateResult, error) {
+	projectDoc, err := cred.getMetaDoc(ctx, projectName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meta of project %s: %w", projectName, err)
+	}
+	project := projectDoc.SelectElement("project")
+	if project == nil {
+		return nil, fmt.Errorf("project not found, name was: %s", projectName)
+	}
+	var packageFlag *etree.Element
+	if packageName != "" {
+		packageDoc, err := cred.getMetaDoc(ctx, projectName, packageName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get meta of package %s/%s: %w", projectName, packageName, err)
+		}
+		if pkg := packageDoc.SelectElement("package"); pkg != nil {
+			packageFlag = pkg.SelectElement("publish")
+		}
+	}
+
+	result := &PublishStateResult{
+		ProjectName:  projectName,
+		PackageName:  packageName,
+		Repositories: []RepositoryPublishState{},
+	}
+	projectFlag := project.SelectElement("publish")
+	for _, repo := range project.SelectElements("repository") {
+		name := repo.SelectAttrValue("name", "")
+		state := flagState(projectFlag, name, true)
+		state = flagState(packageFlag, name, state)
+		result.Repositories = append(result.Repositories, RepositoryPublishState{
+			Repository: name,
+			Enabled:    state,
+		})
+	}
+	return result, nil
+}
+
+func (cred *OSCCredentials) GetPublishState(ctx context.Context, req *mcp.CallToolRequest, params GetPublishStateParam) (*mcp.CallToolResult, *PublishStateResult, error) {
+	slog.Debug("mcp tool call: GetPublishState", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	result, err := cred.getPublishState(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
+
+func (cred *OSCCredentials) SetPublishState(ctx context.Context, req *mcp.CallToolRequest, params SetPublishStateParam) (*mcp.CallToolResult, *PublishStateResult, error) {
+	slog.Debug("mcp tool call: SetPublishState", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	doc, err := cred.getMetaDoc(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := doc.Root()
+	if root == nil {
+		return nil, nil, fmt.Errorf("empty meta for %s", metaPath(params.ProjectName, params.PackageName))
+	}
+
+	setFlag(root, "publish", params.Repository, params.Enable)
+
+	if err := cred.putMetaDoc(ctx, params.ProjectName, params.PackageName, doc); err != nil {
+		return nil, nil, err
+	}
+	result, err := cred.getPublishState(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
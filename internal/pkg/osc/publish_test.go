@@ -0,0 +1,65 @@
+package osc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPublishState(t *testing.T) {
+	projectMeta := `<project name="home:testuser">
+  <title>test</title>
+  <description/>
+  <person userid="testuser" role="maintainer"/>
+  <build>
+    <disable arch="i586"/>
+  </build>
+  <repository name="openSUSE_Tumbleweed">
+    <path project="openSUSE:Factory" repository="snapshot"/>
+    <arch>x86_64</arch>
+  </repository>
+  <repository name="15.6">
+    <path project="openSUSE:Leap:15.6" repository="standard"/>
+    <arch>x86_64</arch>
+  </repository>
+</project>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/source/home:testuser/_meta", r.URL.Path)
+		if r.Method == "PUT" {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			projectMeta = string(body)
+		}
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, projectMeta)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.GetPublishState(context.Background(), &mcp.CallToolRequest{}, GetPublishStateParam{ProjectName: "home:testuser"})
+	assert.NoError(t, err)
+	assert.Equal(t, []RepositoryPublishState{{"openSUSE_Tumbleweed", true}, {"15.6", true}}, result.Repositories)
+
+	_, result, err = cred.SetPublishState(context.Background(), &mcp.CallToolRequest{}, SetPublishStateParam{ProjectName: "home:testuser", Enable: false})
+	assert.NoError(t, err)
+	assert.Equal(t, []RepositoryPublishState{{"openSUSE_Tumbleweed", false}, {"15.6", false}}, result.Repositories)
+	assert.Contains(t, projectMeta, "<disable arch=\"i586\"/>")
+	assert.Less(t, strings.Index(projectMeta, "<build>"), strings.Index(projectMeta, "<publish>"))
+	assert.Less(t, strings.Index(projectMeta, "<publish>"), strings.Index(projectMeta, "<repository"))
+
+	_, result, err = cred.SetPublishState(context.Background(), &mcp.CallToolRequest{}, SetPublishStateParam{ProjectName: "home:testuser", Repository: "15.6", Enable: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []RepositoryPublishState{{"openSUSE_Tumbleweed", false}, {"15.6", true}}, result.Repositories)
+	assert.Equal(t, 1, strings.Count(projectMeta, "<publish>"))
+}
@@ -0,0 +1,81 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type TriggerRebuildParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name,omitempty" jsonschema:"Only rebuild this bundle instead of all bundles of the project."`
+	Repository  string `json:"repository,omitempty" jsonschema:"Only rebuild in this repository."`
+	Arch        string `json:"arch,omitempty" jsonschema:"Only rebuild for this architecture."`
+}
+
+type TriggerRebuildResult struct {
+	Triggered bool   `json:"triggered" jsonschema:"False if there was nothing to rebuild"`
+	Code      string `json:"code,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+// nothingToRebuild reports if the answer of a rebuild means that no build
+// matched the given package, repository and arch.
+func nothingToRebuild(summary string) bool {
+	summary = strings.ToLower(summary)
+	return strings.Contains(summary, "nothing to rebuild") ||
+		strings.Contains(summary, "no packages") ||
+		strings.Contains(summary, "no package found")
+}
+
+func (cred *OSCCredentials) TriggerRebuild(ctx context.Context, req *mcp.CallToolRequest, params TriggerRebuildParam) (*mcp.CallToolResult, *TriggerRebuildResult, error) {
+	slog.Debug("mcp tool call: TriggerRebuild", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "rebuild")
+	if params.PackageName != "" {
+		queryParams.Set("package", params.PackageName)
+	}
+	if params.Repository != "" {
+		queryParams.Set("repository", params.Repository)
+	}
+	if params.Arch != "" {
+		queryParams.Set("arch", params.Arch)
+	}
+	apiURL := fmt.Sprintf("%s/build/%s?%s", cred.GetAPiAddr(), params.ProjectName, queryParams.Encode())
+	httpReq, err := cred.buildRequest(ctx, "POST", apiURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	code, summary := statusSummary(body)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case nothingToRebuild(summary):
+		return nil, &TriggerRebuildResult{Triggered: false, Code: code, Summary: summary}, nil
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+		return nil, nil, fmt.Errorf("no permission to rebuild %s: %s", params.ProjectName, summary)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil, fmt.Errorf("%w: %s", ErrBundleOrProjectNotFound, summary)
+	default:
+		return nil, nil, fmt.Errorf("rebuild failed with status %s: %s %s", resp.Status, code, summary)
+	}
+	return nil, &TriggerRebuildResult{Triggered: true, Code: code, Summary: summary}, nil
+}
@@ -0,0 +1,51 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerRebuild(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "rebuild", r.URL.Query().Get("cmd"))
+		switch r.URL.Path {
+		case "/build/home:testuser":
+			if r.URL.Query().Get("repository") == "15.6" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<status code="not_found"><summary>no packages found</summary></status>`)
+				return
+			}
+			assert.Equal(t, "foo", r.URL.Query().Get("package"))
+			assert.Equal(t, "openSUSE_Tumbleweed", r.URL.Query().Get("repository"))
+			assert.Equal(t, "x86_64", r.URL.Query().Get("arch"))
+			fmt.Fprint(w, `<status code="ok"><summary>Ok</summary></status>`)
+		case "/build/openSUSE:Factory":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<status code="cmd_execution_no_permission"><summary>no permission to execute command 'rebuild'</summary></status>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.TriggerRebuild(context.Background(), nil, TriggerRebuildParam{ProjectName: "home:testuser", PackageName: "foo", Repository: "openSUSE_Tumbleweed", Arch: "x86_64"})
+	assert.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.Equal(t, "ok", result.Code)
+
+	_, result, err = cred.TriggerRebuild(context.Background(), nil, TriggerRebuildParam{ProjectName: "home:testuser", Repository: "15.6"})
+	assert.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.Equal(t, "no packages found", result.Summary)
+
+	_, _, err = cred.TriggerRebuild(context.Background(), nil, TriggerRebuildParam{ProjectName: "openSUSE:Factory"})
+	assert.ErrorContains(t, err, "no permission to rebuild openSUSE:Factory")
+}
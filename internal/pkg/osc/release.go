@@ -0,0 +1,169 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ReleaseTarget struct {
+	Repository       string `json:"repository"`
+	TargetProject    string `json:"target_project"`
+	TargetRepository string `json:"target_repository"`
+	Trigger          string `json:"trigger,omitempty"`
+}
+
+// releaseTargets reads the release targets of the repositories of a project
+// meta. If repository isn't empty, only its targets are returned.
+func releaseTargets(project *etree.Element, repository string) []ReleaseTarget {
+	targets := []ReleaseTarget{}
+	for _, repo := range project.SelectElements("repository") {
+		name := repo.SelectAttrValue("name", "")
+		if repository != "" && name != repository {
+			continue
+		}
+		for _, target := range repo.SelectElements("releasetarget") {
+			targets = append(targets, ReleaseTarget{
+				Repository:       name,
+				TargetProject:    target.SelectAttrValue("project", ""),
+				TargetRepository: target.SelectAttrValue("repository", ""),
+				Trigger:          target.SelectAttrValue("trigger", ""),
+			})
+		}
+	}
+	return targets
+}
+
+// statusSummary returns the code and the summary of a status answer of the
+// api, or the raw body if it isn't one.
+func statusSummary(body []byte) (code, summary string) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(body); err == nil {
+		if status := doc.SelectElement("status"); status != nil {
+			code = status.SelectAttrValue("code", "")
+			if s := status.SelectElement("summary"); s != nil {
+				summary = strings.TrimSpace(s.Text())
+			}
+			return code, summary
+		}
+	}
+	return "", strings.TrimSpace(string(body))
+}
+
+type ReleaseParam struct {
+	ProjectName      string `json:"project_name" jsonschema:"Name of the project to release"`
+	PackageName      string `json:"package_name,omitempty" jsonschema:"Only release this package instead of the whole project."`
+	Repository       string `json:"repository,omitempty" jsonschema:"Only release the binaries of this repository."`
+	TargetProject    string `json:"target_project,omitempty" jsonschema:"Release into this project instead of the configured release targets. Needs target_repository."`
+	TargetRepository string `json:"target_repository,omitempty" jsonschema:"Repository of target_project to release into."`
+}
+
+type ReleaseResult struct {
+	Code    string          `json:"code"`
+	Summary string          `json:"summary,omitempty"`
+	Targets []ReleaseTarget `json:"targets" jsonschema:"The release targets used for the release"`
+}
+
+// checkReleaseTargets returns the targets of a release and fails if there
+// are none or if an explicit target doesn't exist.
+func (cred *OSCCredentials) checkReleaseTargets(ctx context.Context, params ReleaseParam) ([]ReleaseTarget, error) {
+	if params.TargetProject != "" {
+		doc, err := cred.getMetaDoc(ctx, params.TargetProject, "")
+		if errors.Is(err, ErrBundleOrProjectNotFound) {
+			return nil, fmt.Errorf("target project %s doesn't exist", params.TargetProject)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get meta of target project %s: %w", params.TargetProject, err)
+		}
+		found := false
+		if project := doc.SelectElement("project"); project != nil {
+			for _, repo := range project.SelectElements("repository") {
+				found = found || repo.SelectAttrValue("name", "") == params.TargetRepository
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("target project %s has no repository %s", params.TargetProject, params.TargetRepository)
+		}
+		return []ReleaseTarget{{
+			Repository:       params.Repository,
+			TargetProject:    params.TargetProject,
+			TargetRepository: params.TargetRepository,
+		}}, nil
+	}
+
+	doc, err := cred.getMetaDoc(ctx, params.ProjectName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meta of project %s: %w", params.ProjectName, err)
+	}
+	project := doc.SelectElement("project")
+	if project == nil {
+		return nil, fmt.Errorf("project not found, name was: %s", params.ProjectName)
+	}
+	targets := releaseTargets(project, params.Repository)
+	if len(targets) == 0 {
+		if params.Repository != "" {
+			return nil, fmt.Errorf("repository %s of project %s has no release target, set target_project and target_repository", params.Repository, params.ProjectName)
+		}
+		return nil, fmt.Errorf("project %s has no release targets, set target_project and target_repository", params.ProjectName)
+	}
+	return targets, nil
+}
+
+func (cred *OSCCredentials) Release(ctx context.Context, req *mcp.CallToolRequest, params ReleaseParam) (*mcp.CallToolResult, *ReleaseResult, error) {
+	slog.Debug("mcp tool call: Release", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if (params.TargetProject == "") != (params.TargetRepository == "") {
+		return nil, nil, fmt.Errorf("target_project and target_repository must be set together")
+	}
+	targets, err := cred.checkReleaseTargets(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "release")
+	if params.Repository != "" {
+		queryParams.Set("repository", params.Repository)
+	}
+	if params.TargetProject != "" {
+		queryParams.Set("target_project", params.TargetProject)
+		queryParams.Set("target_repository", params.TargetRepository)
+	}
+	apiURL := fmt.Sprintf("%s/source/%s?%s", cred.GetAPiAddr(), params.ProjectName, queryParams.Encode())
+	if params.PackageName != "" {
+		apiURL = fmt.Sprintf("%s/source/%s/%s?%s", cred.GetAPiAddr(), params.ProjectName, params.PackageName, queryParams.Encode())
+	}
+	httpReq, err := cred.buildRequest(ctx, "POST", apiURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	code, summary := statusSummary(body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return nil, nil, fmt.Errorf("no permission to release %s: %s", params.ProjectName, summary)
+	case http.StatusNotFound:
+		return nil, nil, fmt.Errorf("%w: %s", ErrBundleOrProjectNotFound, summary)
+	default:
+		return nil, nil, fmt.Errorf("release failed with status %s: %s %s", resp.Status, code, summary)
+	}
+	return nil, &ReleaseResult{Code: code, Summary: summary, Targets: targets}, nil
+}
@@ -0,0 +1,56 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease(t *testing.T) {
+	released := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/_meta":
+			fmt.Fprint(w, `<project name="home:testuser">
+  <repository name="images">
+    <releasetarget project="home:testuser:release" repository="images" trigger="manual"/>
+    <arch>x86_64</arch>
+  </repository>
+  <repository name="standard"><arch>x86_64</arch></repository>
+</project>`)
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser:missing/_meta":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/source/home:testuser/foo":
+			assert.Equal(t, "release", r.URL.Query().Get("cmd"))
+			released = true
+			fmt.Fprint(w, `<status code="ok"><summary>Ok</summary></status>`)
+		case r.Method == "POST" && r.URL.Path == "/source/home:testuser":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<status code="cmd_execution_no_permission"><summary>no permission to modify project home:testuser:release</summary></status>`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.Release(context.Background(), nil, ReleaseParam{ProjectName: "home:testuser", PackageName: "foo"})
+	assert.NoError(t, err)
+	assert.True(t, released)
+	assert.Equal(t, "ok", result.Code)
+	assert.Equal(t, []ReleaseTarget{{Repository: "images", TargetProject: "home:testuser:release", TargetRepository: "images", Trigger: "manual"}}, result.Targets)
+
+	_, _, err = cred.Release(context.Background(), nil, ReleaseParam{ProjectName: "home:testuser"})
+	assert.ErrorContains(t, err, "no permission to modify project")
+
+	_, _, err = cred.Release(context.Background(), nil, ReleaseParam{ProjectName: "home:testuser", Repository: "standard"})
+	assert.ErrorContains(t, err, "has no release target")
+
+	_, _, err = cred.Release(context.Background(), nil, ReleaseParam{ProjectName: "home:testuser", TargetProject: "home:testuser:missing", TargetRepository: "images"})
+	assert.ErrorContains(t, err, "doesn't exist")
+}
@@ -0,0 +1,138 @@
+package osc
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+type repoMd struct {
+	Data []struct {
+		Type     string `xml:"type,attr"`
+		Location struct {
+			Href string `xml:"href,attr"`
+		} `xml:"location"`
+	} `xml:"data"`
+}
+
+type primaryPackage struct {
+	Name    string `xml:"name"`
+	Arch    string `xml:"arch"`
+	Version struct {
+		Epoch string `xml:"epoch,attr"`
+		Ver   string `xml:"ver,attr"`
+		Rel   string `xml:"rel,attr"`
+	} `xml:"version"`
+	Location struct {
+		Href string `xml:"href,attr"`
+	} `xml:"location"`
+	Provides []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"format>provides>entry"`
+}
+
+// primaryLocation returns the location of the primary metadata in the
+// repomd.xml of a repository.
+func primaryLocation(repomd io.Reader) (string, error) {
+	var md repoMd
+	if err := xml.NewDecoder(repomd).Decode(&md); err != nil {
+		return "", fmt.Errorf("failed to parse repomd.xml: %w", err)
+	}
+	for _, data := range md.Data {
+		if data.Type == "primary" && data.Location.Href != "" {
+			return data.Location.Href, nil
+		}
+	}
+	return "", fmt.Errorf("repomd.xml has no primary metadata")
+}
+
+// matchPrimary returns the packages of the primary metadata of a repository
+// which provide a capability matching the pattern of params. The matching
+// capabilities are returned in Provides. The metadata is decoded package by
+// package, as it can be several hundred megabytes for big distributions.
+func matchPrimary(primary io.Reader, repoPath string, params SearchPackagesParams, match func(string) bool) ([]rpm_pack, error) {
+	archMatch := archMatcher(params)
+	packages := []rpm_pack{}
+	decoder := xml.NewDecoder(primary)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse primary metadata: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "package" {
+			continue
+		}
+		var pkg primaryPackage
+		if err := decoder.DecodeElement(&pkg, &start); err != nil {
+			return nil, fmt.Errorf("failed to parse primary metadata: %w", err)
+		}
+		if !archMatch(pkg.Arch) {
+			continue
+		}
+		provides := []string{}
+		for _, entry := range pkg.Provides {
+			if match(entry.Name) {
+				provides = append(provides, entry.Name)
+			}
+		}
+		if len(provides) == 0 {
+			continue
+		}
+		epoch := pkg.Version.Epoch
+		if epoch == "0" {
+			epoch = ""
+		}
+		packages = append(packages, rpm_pack{
+			Name:       pkg.Name,
+			Arch:       pkg.Arch,
+			Version:    pkg.Version.Ver + "-" + pkg.Version.Rel,
+			Epoch:      epoch,
+			Repository: path.Join(repoPath, path.Dir(pkg.Location.Href)),
+			Provides:   provides,
+		})
+	}
+	return packages, nil
+}
+
+// searchProvides searches the capabilities provided by the packages of a
+// repository. repomd.xml and the primary metadata are cached like the
+// INDEX.gz, the file name of the primary metadata changes with its content.
+func (cred OSCCredentials) searchProvides(ctx context.Context, repoURL, cacheDir, repoPath string, params SearchPackagesParams, match func(string) bool) ([]rpm_pack, error) {
+	repomdFile := indexCacheFile(cacheDir, repoPath, "repodata/repomd.xml")
+	if err := cred.fetchIndex(ctx, repoURL+"/repodata/repomd.xml", repomdFile); err != nil {
+		return nil, err
+	}
+	repomd, err := os.Open(repomdFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file: %w", err)
+	}
+	href, err := primaryLocation(repomd)
+	repomd.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	primaryFile := indexCacheFile(cacheDir, repoPath, href)
+	if err := cred.fetchIndex(ctx, repoURL+"/"+href, primaryFile); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(primaryFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gz.Close()
+	return matchPrimary(gz, repoPath, params, match)
+}
@@ -0,0 +1,93 @@
+package osc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRepomd = `<?xml version="1.0" encoding="UTF-8"?>
+<repomd xmlns="http://linux.duke.edu/metadata/repo" xmlns:rpm="http://linux.duke.edu/metadata/rpm">
+  <data type="filelists"><location href="repodata/abc-filelists.xml.gz"/></data>
+  <data type="primary"><location href="repodata/def-primary.xml.gz"/></data>
+</repomd>`
+
+const testPrimary = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://linux.duke.edu/metadata/common" xmlns:rpm="http://linux.duke.edu/metadata/rpm" packages="3">
+<package type="rpm">
+  <name>libfoo1</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="1.2" rel="3.1"/>
+  <location href="x86_64/libfoo1-1.2-3.1.x86_64.rpm"/>
+  <format>
+    <rpm:provides>
+      <rpm:entry name="libfoo.so.1()(64bit)"/>
+      <rpm:entry name="libfoo1" flags="EQ" epoch="0" ver="1.2" rel="3.1"/>
+    </rpm:provides>
+  </format>
+</package>
+<package type="rpm">
+  <name>libfoo1</name>
+  <arch>aarch64</arch>
+  <version epoch="2" ver="1.2" rel="3.1"/>
+  <location href="aarch64/libfoo1-1.2-3.1.aarch64.rpm"/>
+  <format>
+    <rpm:provides>
+      <rpm:entry name="libfoo.so.1()(64bit)"/>
+    </rpm:provides>
+  </format>
+</package>
+<package type="rpm">
+  <name>bar</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="2.0" rel="1.1"/>
+  <location href="x86_64/bar-2.0-1.1.x86_64.rpm"/>
+  <format>
+    <rpm:provides>
+      <rpm:entry name="bar"/>
+    </rpm:provides>
+  </format>
+</package>
+</metadata>`
+
+func TestSearchProvides(t *testing.T) {
+	var primary bytes.Buffer
+	gz := gzip.NewWriter(&primary)
+	_, _ = gz.Write([]byte(testPrimary))
+	assert.NoError(t, gz.Close())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repo/repodata/repomd.xml":
+			_, _ = w.Write([]byte(testRepomd))
+		case "/repo/repodata/def-primary.xml.gz":
+			_, _ = w.Write(primary.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := OSCCredentials{}
+	params := SearchPackagesParams{Pattern: "libfoo.so.1", MatchProvides: true}
+	match, err := nameMatcher(params)
+	assert.NoError(t, err)
+	packages, err := cred.searchProvides(context.Background(), server.URL+"/repo", t.TempDir(), "/repositories/home:/user/standard", params, match)
+	assert.NoError(t, err)
+	assert.Equal(t, []rpm_pack{
+		{Name: "libfoo1", Arch: "x86_64", Version: "1.2-3.1", Repository: "/repositories/home:/user/standard/x86_64", Provides: []string{"libfoo.so.1()(64bit)"}},
+		{Name: "libfoo1", Arch: "aarch64", Version: "1.2-3.1", Epoch: "2", Repository: "/repositories/home:/user/standard/aarch64", Provides: []string{"libfoo.so.1()(64bit)"}},
+	}, packages)
+
+	params.Arches = []string{"aarch64"}
+	packages, err = cred.searchProvides(context.Background(), server.URL+"/repo", t.TempDir(), "/repositories/home:/user/standard", params, match)
+	assert.NoError(t, err)
+	assert.Len(t, packages, 1)
+
+	_, err = cred.searchProvides(context.Background(), server.URL+"/missing", t.TempDir(), "/repositories/home:/user/standard", params, match)
+	assert.ErrorIs(t, err, ErrIndexNotFound)
+}
@@ -1,6 +1,7 @@
 package osc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,15 +24,56 @@ func ListRequestsInputSchema() *jsonschema.Schema {
 }
 
 type ListRequestsCmd struct {
-	User         string `json:"user,omitempty" jsonschema:"Username to get requests for. If not provided, it will use the configured user."`
-	Group        string `json:"group,omitempty" jsonschema:"Group name to filter requests."`
-	Project      string `json:"project,omitempty" jsonschema:"Project name to filter requests."`
-	Package      string `json:"package,omitempty" jsonschema:"Package name to filter requests."`
-	States       string `json:"states,omitempty" jsonschema:"Comma-separated list of request states (e.g., 'new,review')"`
-	ReviewStates string `json:"reviewstates,omitempty" jsonschema:"Comma-separated list of review states."`
-	Types        string `json:"types,omitempty" jsonschema:"Comma-separated list of action types."`
-	Limit        int    `json:"limit,omitempty" jsonschema:"Limit number of requests."`
-	Ids          string `json:"ids,omitempty" jsonschema:"Comma-separated list of request IDs."`
+	User          string `json:"user,omitempty" jsonschema:"Username to get requests for. If not provided, it will use the configured user."`
+	Group         string `json:"group,omitempty" jsonschema:"Group name to filter requests."`
+	Project       string `json:"project,omitempty" jsonschema:"Project name to filter requests."`
+	Package       string `json:"package,omitempty" jsonschema:"Package name to filter requests."`
+	States        string `json:"states,omitempty" jsonschema:"Comma-separated list of request states (e.g., 'new,review')"`
+	ReviewStates  string `json:"reviewstates,omitempty" jsonschema:"Comma-separated list of review states."`
+	Types         string `json:"types,omitempty" jsonschema:"Comma-separated list of action types."`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Limit number of requests."`
+	Ids           string `json:"ids,omitempty" jsonschema:"Comma-separated list of request IDs."`
+	Mode          string `json:"mode,omitempty" jsonschema:"outgoing: requests created by the user, incoming: requests to packages maintained by the user, review: requests waiting for a review of the user. Lists all requests of the user if empty."`
+	CreatedAfter  string `json:"created_after,omitempty" jsonschema:"Only list requests created after this time, given in RFC3339 format like 2025-01-31T00:00:00Z."`
+	CreatedBefore string `json:"created_before,omitempty" jsonschema:"Only list requests created before this time, given in RFC3339 format."`
+}
+
+// parseRequestTime parses the creation time of a request, which the api
+// returns without a time zone in UTC.
+func parseRequestTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05", value)
+}
+
+// filterRequestsByDate keeps the requests created in the given interval, a
+// zero time means no bound. At most limit requests are kept if limit > 0.
+func filterRequestsByDate(requests []ShortRequest, after, before time.Time, limit int) []ShortRequest {
+	filtered := make([]ShortRequest, 0, len(requests))
+	for _, r := range requests {
+		created, err := parseRequestTime(r.Created)
+		if err != nil {
+			slog.Warn("invalid creation time of request", "id", r.ID, "created", r.Created)
+			continue
+		}
+		if (!after.IsZero() && !created.After(after)) || (!before.IsZero() && !created.Before(before)) {
+			continue
+		}
+		if limit > 0 && len(filtered) >= limit {
+			break
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// requestModes maps the modes of ListRequestsCmd to the roles and the
+// default states and review states of the request collection query.
+var requestModes = map[string]struct{ roles, states, reviewStates string }{
+	"outgoing": {roles: "creator"},
+	"incoming": {roles: "maintainer"},
+	"review":   {roles: "reviewer", states: "review", reviewStates: "new"},
 }
 
 type GetRequestCmd struct {
@@ -95,14 +138,15 @@ type RequestAction struct {
 type RequestSource struct {
 	XMLName xml.Name `xml:"source"`
 	Project string   `xml:"project,attr"`
-	Package string   `xml:"package,attr"`
-	Rev     string   `xml:"rev,attr"`
+	Package string   `xml:"package,attr,omitempty"`
+	Rev     string   `xml:"rev,attr,omitempty"`
 }
 
 type RequestTarget struct {
-	XMLName xml.Name `xml:"target"`
-	Project string   `xml:"project,attr"`
-	Package string   `xml:"package,attr"`
+	XMLName        xml.Name `xml:"target"`
+	Project        string   `xml:"project,attr"`
+	Package        string   `xml:"package,attr,omitempty"`
+	ReleaseProject string   `xml:"releaseproject,attr,omitempty" json:",omitempty"`
 }
 
 type RequestPerson struct {
@@ -124,12 +168,165 @@ type RequestState struct {
 	Superseded string `xml:"superseded_by,attr"`
 }
 
+type CreateSubmitRequestCmd struct {
+	SourceProject string `json:"source_project" jsonschema:"Project which contains the changed package, e.g. the branch project."`
+	SourcePackage string `json:"source_package" jsonschema:"Package to submit."`
+	TargetProject string `json:"target_project" jsonschema:"Project to submit the package to."`
+	TargetPackage string `json:"target_package,omitempty" jsonschema:"Name of the package in the target project. Defaults to the source package."`
+	Revision      string `json:"revision,omitempty" jsonschema:"Revision of the source package to submit. Defaults to the latest revision."`
+	Description   string `json:"description" jsonschema:"Description of the changes."`
+}
+
+func (cred *OSCCredentials) CreateSubmitRequest(ctx context.Context, req *mcp.CallToolRequest, params CreateSubmitRequestCmd) (*mcp.CallToolResult, *CreateRequestResult, error) {
+	slog.Debug("mcp tool call: CreateSubmitRequest", "params", params)
+	if params.SourceProject == "" || params.SourcePackage == "" {
+		return nil, nil, fmt.Errorf("source project and package must be specified")
+	}
+	if params.TargetProject == "" {
+		return nil, nil, fmt.Errorf("target project must be specified")
+	}
+	if params.Description == "" {
+		return nil, nil, fmt.Errorf("description must be specified")
+	}
+	targetPackage := params.TargetPackage
+	if targetPackage == "" {
+		targetPackage = params.SourcePackage
+	}
+	action := RequestAction{
+		Type: "submit",
+		Source: RequestSource{
+			Project: params.SourceProject,
+			Package: params.SourcePackage,
+			Rev:     params.Revision,
+		},
+		Target: RequestTarget{
+			Project: params.TargetProject,
+			Package: targetPackage,
+		},
+	}
+	request, err := cred.createRequest(ctx, []RequestAction{action}, params.Description)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &CreateRequestResult{ID: request.ID, State: request.State.Name}, nil
+}
+
+type CreateMaintenanceRequestCmd struct {
+	SourceProject  string `json:"source_project" jsonschema:"Project which contains the fixed package."`
+	SourcePackage  string `json:"source_package,omitempty" jsonschema:"Package with the fix. If empty, all packages of the source project are used."`
+	TargetProject  string `json:"target_project" jsonschema:"Maintenance project in which the incident is created, e.g. openSUSE:Maintenance."`
+	ReleaseProject string `json:"release_project,omitempty" jsonschema:"Update project to which the incident is released, e.g. openSUSE:Leap:15.6:Update."`
+	Description    string `json:"description" jsonschema:"Description of the update."`
+}
+
+type CreateRequestResult struct {
+	ID    string `json:"id"`
+	State string `json:"state,omitempty"`
+}
+
+// newRequest is the body of a request which is created
+type newRequest struct {
+	XMLName     xml.Name        `xml:"request"`
+	Actions     []RequestAction `xml:"action"`
+	Description string          `xml:"description,omitempty"`
+}
+
+// createRequest creates a request with the given actions and returns the
+// request as stored on the server.
+func (cred *OSCCredentials) createRequest(ctx context.Context, actions []RequestAction, description string) (*Request, error) {
+	xmlData, err := xml.MarshalIndent(newRequest{Actions: actions, Description: description}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request xml: %w", err)
+	}
+	createURL := fmt.Sprintf("%s/request?cmd=create", cred.GetAPiAddr())
+	slog.Debug("Creating request", "url", createURL, "xml", string(xmlData))
+
+	oscReq, err := cred.buildRequest(ctx, "POST", createURL, bytes.NewReader(xmlData))
+	if err != nil {
+		return nil, err
+	}
+	oscReq.Header.Set("Content-Type", "application/xml")
+	resp, err := cred.do(oscReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to create request: status %s, body: %s", resp.Status, string(body))
+	}
+	var request Request
+	if err := xml.Unmarshal(body, &request); err != nil {
+		slog.Debug("error on decode", "err", err, "xml", string(body))
+		return nil, err
+	}
+	slog.Info("Request created", "id", request.ID)
+	return &request, nil
+}
+
+func (cred *OSCCredentials) CreateMaintenanceRequest(ctx context.Context, req *mcp.CallToolRequest, params CreateMaintenanceRequestCmd) (*mcp.CallToolResult, *CreateRequestResult, error) {
+	slog.Debug("mcp tool call: CreateMaintenanceRequest", "params", params)
+	if params.SourceProject == "" {
+		return nil, nil, fmt.Errorf("source project must be specified")
+	}
+	if params.TargetProject == "" {
+		return nil, nil, fmt.Errorf("target maintenance project must be specified")
+	}
+	if params.Description == "" {
+		return nil, nil, fmt.Errorf("description must be specified")
+	}
+	action := RequestAction{
+		Type: "maintenance_incident",
+		Source: RequestSource{
+			Project: params.SourceProject,
+			Package: params.SourcePackage,
+		},
+		Target: RequestTarget{
+			Project:        params.TargetProject,
+			ReleaseProject: params.ReleaseProject,
+		},
+	}
+	request, err := cred.createRequest(ctx, []RequestAction{action}, params.Description)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &CreateRequestResult{ID: request.ID, State: request.State.Name}, nil
+}
+
 func (cred *OSCCredentials) ListRequests(ctx context.Context, req *mcp.CallToolRequest, params ListRequestsCmd) (*mcp.CallToolResult, *RequestCollection, error) {
+	var after, before time.Time
+	var err error
+	if params.CreatedAfter != "" {
+		if after, err = time.Parse(time.RFC3339, params.CreatedAfter); err != nil {
+			return nil, nil, fmt.Errorf("invalid created_after: %w", err)
+		}
+	}
+	if params.CreatedBefore != "" {
+		if before, err = time.Parse(time.RFC3339, params.CreatedBefore); err != nil {
+			return nil, nil, fmt.Errorf("invalid created_before: %w", err)
+		}
+	}
+	filterDate := !after.IsZero() || !before.IsZero()
 	baseURL := fmt.Sprintf("%s/request", cred.GetAPiAddr())
 	queryParams := url.Values{}
 	queryParams.Set("view", "collection")
 
-	mustSetuser := true
+	if params.Mode != "" {
+		mode, ok := requestModes[params.Mode]
+		if !ok {
+			return nil, nil, fmt.Errorf("mode must be outgoing, incoming or review, got '%s'", params.Mode)
+		}
+		queryParams.Set("roles", mode.roles)
+		if params.States == "" && mode.states != "" {
+			params.States = mode.states
+		}
+		if params.ReviewStates == "" && mode.reviewStates != "" {
+			params.ReviewStates = mode.reviewStates
+		}
+	}
 	if params.Group != "" {
 		queryParams.Set("group", params.Group)
 	}
@@ -150,14 +347,15 @@ func (cred *OSCCredentials) ListRequests(ctx context.Context, req *mcp.CallToolR
 	if params.Types != "" {
 		queryParams.Set("types", params.Types)
 	}
-	if params.Limit > 0 {
+	// the api can't filter by date, so the limit is applied after filtering
+	if params.Limit > 0 && !filterDate {
 		queryParams.Set("limit", strconv.Itoa(params.Limit))
 	}
 	user := params.User
-	if mustSetuser {
+	if user == "" {
 		user = cred.Name
-		queryParams.Set("user", user)
 	}
+	queryParams.Set("user", user)
 	// always use full history
 	queryParams.Set("withfullhistory", "1")
 
@@ -168,7 +366,7 @@ func (cred *OSCCredentials) ListRequests(ctx context.Context, req *mcp.CallToolR
 	if err != nil {
 		return nil, nil, err
 	}
-	resp, err := http.DefaultClient.Do(oscReq)
+	resp, err := cred.do(oscReq)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -189,6 +387,10 @@ func (cred *OSCCredentials) ListRequests(ctx context.Context, req *mcp.CallToolR
 	if requests.Requests == nil {
 		requests.Requests = make([]ShortRequest, 0)
 	}
+	if filterDate {
+		requests.Requests = filterRequestsByDate(requests.Requests, after, before, params.Limit)
+		requests.Matches = strconv.Itoa(len(requests.Requests))
+	}
 	for i := range requests.Requests {
 		if requests.Requests[i].Actions == nil {
 			requests.Requests[i].Actions = make([]RequestAction, 0)
@@ -213,7 +415,7 @@ func (cred *OSCCredentials) getRequestDiff(ctx context.Context, requestId string
 	if err != nil {
 		return "", err
 	}
-	resp, err := http.DefaultClient.Do(oscReq)
+	resp, err := cred.do(oscReq)
 	if err != nil {
 		return "", err
 	}
@@ -232,8 +434,9 @@ func (cred *OSCCredentials) getRequestDiff(ctx context.Context, requestId string
 	return string(body), nil
 }
 
-func (cred *OSCCredentials) GetRequest(ctx context.Context, req *mcp.CallToolRequest, params GetRequestCmd) (*mcp.CallToolResult, *Request, error) {
-	baseURL := fmt.Sprintf("%s/request/%s", cred.GetAPiAddr(), params.Id)
+// getRequestInternal fetches a single request including its history and reviews.
+func (cred *OSCCredentials) getRequestInternal(ctx context.Context, id string) (*Request, error) {
+	baseURL := fmt.Sprintf("%s/request/%s", cred.GetAPiAddr(), id)
 	queryParams := url.Values{}
 	// always get the history
 	queryParams.Set("withhistory", "1")
@@ -244,33 +447,25 @@ func (cred *OSCCredentials) GetRequest(ctx context.Context, req *mcp.CallToolReq
 	slog.Debug("Getting request from OBS", "url", fullURL)
 	oscReq, err := cred.buildRequest(ctx, "GET", fullURL, nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(oscReq)
+	resp, err := cred.do(oscReq)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, nil, fmt.Errorf("failed to get request: status %s, body: %s", resp.Status, string(body))
+		return nil, fmt.Errorf("failed to get request: status %s, body: %s", resp.Status, string(body))
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	var request Request
 	if err := xml.Unmarshal(body, &request); err != nil {
 		slog.Debug("error on decode", "err", err, "xml", string(body))
-		return nil, nil, err
-	}
-
-	diff, err := cred.getRequestDiff(ctx, params.Id)
-	if err != nil {
-		slog.Warn("could not get request diff", "err", err, "request_id", params.Id)
-		request.Diff = fmt.Sprintf("Could not retrieve diff: %v", err)
-	} else {
-		request.Diff = diff
+		return nil, err
 	}
 
 	if request.Actions == nil {
@@ -290,5 +485,314 @@ func (cred *OSCCredentials) GetRequest(ctx context.Context, req *mcp.CallToolReq
 	if request.Reviews == nil {
 		request.Reviews = make([]Review, 0)
 	}
-	return nil, &request, nil
+	return &request, nil
+}
+
+func (cred *OSCCredentials) GetRequest(ctx context.Context, req *mcp.CallToolRequest, params GetRequestCmd) (*mcp.CallToolResult, *Request, error) {
+	request, err := cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diff, err := cred.getRequestDiff(ctx, params.Id)
+	if err != nil {
+		slog.Warn("could not get request diff", "err", err, "request_id", params.Id)
+		request.Diff = fmt.Sprintf("Could not retrieve diff: %v", err)
+	} else {
+		request.Diff = diff
+	}
+	return nil, request, nil
+}
+
+type GetRequestActionDiffCmd struct {
+	Id          string `json:"id" jsonschema:"Request ID."`
+	ActionIndex int    `json:"action_index,omitempty" jsonschema:"Index of the action in the request, 0 is the first action."`
+}
+
+type RequestActionDiffResult struct {
+	Id          string        `json:"id"`
+	ActionIndex int           `json:"action_index"`
+	NrActions   int           `json:"nr_actions"`
+	Action      RequestAction `json:"action"`
+	Diff        string        `json:"diff,omitempty"`
+	Note        string        `json:"note,omitempty"`
+}
+
+// GetRequestActionDiff returns the diff of a single action of a request, which
+// is the diff of the source package against the target package.
+func (cred *OSCCredentials) GetRequestActionDiff(ctx context.Context, req *mcp.CallToolRequest, params GetRequestActionDiffCmd) (*mcp.CallToolResult, *RequestActionDiffResult, error) {
+	slog.Debug("mcp tool call: GetRequestActionDiff", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("request ID must be specified")
+	}
+	request, err := cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if params.ActionIndex < 0 || params.ActionIndex >= len(request.Actions) {
+		return nil, nil, fmt.Errorf("action index %d is out of range, request %s has %d actions", params.ActionIndex, params.Id, len(request.Actions))
+	}
+	action := request.Actions[params.ActionIndex]
+	result := &RequestActionDiffResult{
+		Id:          params.Id,
+		ActionIndex: params.ActionIndex,
+		NrActions:   len(request.Actions),
+		Action:      action,
+	}
+	if action.Type == "delete" {
+		result.Note = "The action deletes the target, there is no diff."
+		return nil, result, nil
+	}
+	if action.Source.Project == "" || action.Source.Package == "" {
+		result.Note = fmt.Sprintf("The action of type %s has no source package, there is no diff.", action.Type)
+		return nil, result, nil
+	}
+	targetPackage := action.Target.Package
+	if targetPackage == "" {
+		targetPackage = action.Source.Package
+	}
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "diff")
+	queryParams.Set("expand", "1")
+	queryParams.Set("oproject", action.Target.Project)
+	queryParams.Set("opackage", targetPackage)
+	if action.Source.Rev != "" {
+		queryParams.Set("rev", action.Source.Rev)
+	}
+	diff, _, err := cred.postSourceDiff(ctx, action.Source.Project, action.Source.Package, queryParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Diff = diff
+	return nil, result, nil
+}
+
+type SetReviewStateCmd struct {
+	Id        string `json:"id" jsonschema:"Request ID."`
+	State     string `json:"state" jsonschema:"New state of the review, either accepted or declined."`
+	ByUser    string `json:"by_user,omitempty" jsonschema:"User the review is assigned to."`
+	ByGroup   string `json:"by_group,omitempty" jsonschema:"Group the review is assigned to."`
+	ByProject string `json:"by_project,omitempty" jsonschema:"Project the review is assigned to."`
+	ByPackage string `json:"by_package,omitempty" jsonschema:"Package the review is assigned to, only valid together with by_project."`
+	Comment   string `json:"comment,omitempty" jsonschema:"Comment for the review decision."`
+}
+
+type SetReviewStateResult struct {
+	Id      string   `json:"id"`
+	Reviews []Review `json:"reviews"`
+}
+
+// changeRequest posts a command with the given parameters to a request.
+func (cred *OSCCredentials) changeRequest(ctx context.Context, id string, queryParams url.Values) error {
+	fullURL := fmt.Sprintf("%s/request/%s?%s", cred.GetAPiAddr(), id, queryParams.Encode())
+	slog.Debug("Changing request", "url", fullURL)
+	oscReq, err := cred.buildRequest(ctx, "POST", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := cred.do(oscReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to change request %s: status %s, body: %s", id, resp.Status, string(body))
+	}
+	return nil
+}
+
+func (cred *OSCCredentials) SetReviewState(ctx context.Context, req *mcp.CallToolRequest, params SetReviewStateCmd) (*mcp.CallToolResult, *SetReviewStateResult, error) {
+	slog.Debug("mcp tool call: SetReviewState", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("request ID must be specified")
+	}
+	if params.State != "accepted" && params.State != "declined" {
+		return nil, nil, fmt.Errorf("state must be accepted or declined, got '%s'", params.State)
+	}
+	nrBy := 0
+	for _, by := range []string{params.ByUser, params.ByGroup, params.ByProject} {
+		if by != "" {
+			nrBy++
+		}
+	}
+	if nrBy != 1 {
+		return nil, nil, fmt.Errorf("exactly one of by_user, by_group or by_project must be set")
+	}
+	if params.ByPackage != "" && params.ByProject == "" {
+		return nil, nil, fmt.Errorf("by_package can only be used together with by_project")
+	}
+
+	request, err := cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := false
+	for _, review := range request.Reviews {
+		if review.State == "new" && review.ByUser == params.ByUser && review.ByGroup == params.ByGroup &&
+			review.ByProject == params.ByProject && review.ByPackage == params.ByPackage {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("no pending review matching the given by_* selector found in request %s", params.Id)
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "changereviewstate")
+	queryParams.Set("newstate", params.State)
+	if params.ByUser != "" {
+		queryParams.Set("by_user", params.ByUser)
+	}
+	if params.ByGroup != "" {
+		queryParams.Set("by_group", params.ByGroup)
+	}
+	if params.ByProject != "" {
+		queryParams.Set("by_project", params.ByProject)
+	}
+	if params.ByPackage != "" {
+		queryParams.Set("by_package", params.ByPackage)
+	}
+	if params.Comment != "" {
+		queryParams.Set("comment", params.Comment)
+	}
+	if err := cred.changeRequest(ctx, params.Id, queryParams); err != nil {
+		return nil, nil, err
+	}
+
+	request, err = cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &SetReviewStateResult{Id: params.Id, Reviews: request.Reviews}, nil
+}
+
+type ResolveSupersedeChainCmd struct {
+	Id string `json:"id" jsonschema:"Request ID to start from."`
+}
+
+type SupersedeChainEntry struct {
+	Id           string `json:"id"`
+	State        string `json:"state"`
+	SupersededBy string `json:"superseded_by,omitempty"`
+}
+
+type ResolveSupersedeChainResult struct {
+	Chain   []SupersedeChainEntry `json:"chain"`
+	Current string                `json:"current"`
+	Cycle   bool                  `json:"cycle,omitempty"`
+}
+
+func (cred *OSCCredentials) ResolveSupersedeChain(ctx context.Context, req *mcp.CallToolRequest, params ResolveSupersedeChainCmd) (*mcp.CallToolResult, *ResolveSupersedeChainResult, error) {
+	slog.Debug("mcp tool call: ResolveSupersedeChain", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("request ID must be specified")
+	}
+	result := &ResolveSupersedeChainResult{
+		Chain: []SupersedeChainEntry{},
+	}
+	visited := make(map[string]bool)
+	id := params.Id
+	for id != "" {
+		if visited[id] {
+			slog.Warn("cycle in superseded chain", "request_id", id)
+			result.Cycle = true
+			break
+		}
+		visited[id] = true
+		request, err := cred.getRequestInternal(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to follow superseded chain at request %s: %w", id, err)
+		}
+		result.Chain = append(result.Chain, SupersedeChainEntry{
+			Id:           id,
+			State:        request.State.Name,
+			SupersededBy: request.State.Superseded,
+		})
+		result.Current = id
+		id = request.State.Superseded
+	}
+	return nil, result, nil
+}
+
+type AddReviewToRequestCmd struct {
+	Id        string `json:"id" jsonschema:"Request ID."`
+	ByUser    string `json:"by_user,omitempty" jsonschema:"User who should review the request."`
+	ByGroup   string `json:"by_group,omitempty" jsonschema:"Group which should review the request."`
+	ByProject string `json:"by_project,omitempty" jsonschema:"Maintainers of this project should review the request."`
+	ByPackage string `json:"by_package,omitempty" jsonschema:"Maintainers of this package should review the request, only valid together with by_project."`
+	Comment   string `json:"comment,omitempty" jsonschema:"Reason for the review."`
+}
+
+func (cred *OSCCredentials) AddReviewToRequest(ctx context.Context, req *mcp.CallToolRequest, params AddReviewToRequestCmd) (*mcp.CallToolResult, *SetReviewStateResult, error) {
+	slog.Debug("mcp tool call: AddReviewToRequest", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("request ID must be specified")
+	}
+	if params.ByUser == "" && params.ByGroup == "" && params.ByProject == "" {
+		return nil, nil, fmt.Errorf("one of by_user, by_group or by_project must be set")
+	}
+	if params.ByPackage != "" && params.ByProject == "" {
+		return nil, nil, fmt.Errorf("by_package can only be used together with by_project")
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "addreview")
+	if params.ByUser != "" {
+		queryParams.Set("by_user", params.ByUser)
+	}
+	if params.ByGroup != "" {
+		queryParams.Set("by_group", params.ByGroup)
+	}
+	if params.ByProject != "" {
+		queryParams.Set("by_project", params.ByProject)
+	}
+	if params.ByPackage != "" {
+		queryParams.Set("by_package", params.ByPackage)
+	}
+	if params.Comment != "" {
+		queryParams.Set("comment", params.Comment)
+	}
+	if err := cred.changeRequest(ctx, params.Id, queryParams); err != nil {
+		return nil, nil, err
+	}
+
+	request, err := cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &SetReviewStateResult{Id: params.Id, Reviews: request.Reviews}, nil
+}
+
+type ChangeRequestStateCmd struct {
+	Id      string `json:"id" jsonschema:"Request ID"`
+	State   string `json:"state" jsonschema:"New state of the request, one of accepted, declined or revoked. Only the creator can revoke a request."`
+	Comment string `json:"comment,omitempty" jsonschema:"Reason for the decision."`
+}
+
+func (cred *OSCCredentials) ChangeRequestState(ctx context.Context, req *mcp.CallToolRequest, params ChangeRequestStateCmd) (*mcp.CallToolResult, *Request, error) {
+	slog.Debug("mcp tool call: ChangeRequestState", "params", params)
+	if params.Id == "" {
+		return nil, nil, fmt.Errorf("request ID must be specified")
+	}
+	if params.State != "accepted" && params.State != "declined" && params.State != "revoked" {
+		return nil, nil, fmt.Errorf("state must be accepted, declined or revoked, got '%s'", params.State)
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("cmd", "changestate")
+	queryParams.Set("newstate", params.State)
+	if params.Comment != "" {
+		queryParams.Set("comment", params.Comment)
+	}
+	if err := cred.changeRequest(ctx, params.Id, queryParams); err != nil {
+		return nil, nil, err
+	}
+
+	request, err := cred.getRequestInternal(ctx, params.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, request, nil
 }
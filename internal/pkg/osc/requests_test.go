@@ -2,7 +2,9 @@ package osc
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -67,6 +69,107 @@ func TestListRequests(t *testing.T) {
 	assert.Equal(t, "Please review my package.", requests.Requests[0].Description)
 }
 
+func TestListRequestsMode(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<collection matches="0"/>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, _, err := cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "outgoing"})
+	assert.NoError(t, err)
+	assert.Equal(t, "creator", query.Get("roles"))
+	assert.Equal(t, "new,review", query.Get("states"))
+	assert.Equal(t, "testuser", query.Get("user"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "review"})
+	assert.NoError(t, err)
+	assert.Equal(t, "reviewer", query.Get("roles"))
+	assert.Equal(t, "review", query.Get("states"))
+	assert.Equal(t, "new", query.Get("reviewstates"))
+
+	assert.Equal(t, "testuser", query.Get("user"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "review", User: "otheruser"})
+	assert.NoError(t, err)
+	assert.Equal(t, "reviewer", query.Get("roles"))
+	assert.Equal(t, "otheruser", query.Get("user"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "outgoing", User: "otheruser"})
+	assert.NoError(t, err)
+	assert.Equal(t, "creator", query.Get("roles"))
+	assert.Equal(t, "otheruser", query.Get("user"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "incoming", States: "new"})
+	assert.NoError(t, err)
+	assert.Equal(t, "maintainer", query.Get("roles"))
+	assert.Equal(t, "new", query.Get("states"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Mode: "all"})
+	assert.Error(t, err)
+}
+
+func TestListRequestsCreatedFilter(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<collection matches="4">
+  <request id="1" created="2025-01-10T08:00:00"><state name="new"/></request>
+  <request id="2" created="2025-02-10T08:00:00"><state name="new"/></request>
+  <request id="3" created="2025-03-10T08:00:00"><state name="new"/></request>
+  <request id="4" created="2025-04-10T08:00:00"><state name="new"/></request>
+</collection>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+	ids := func(requests []ShortRequest) []string {
+		var ids []string
+		for _, r := range requests {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	_, result, err := cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{CreatedAfter: "2025-02-01T00:00:00Z"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "4"}, ids(result.Requests))
+	assert.Equal(t, "3", result.Matches)
+
+	_, result, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{
+		CreatedAfter:  "2025-02-01T00:00:00Z",
+		CreatedBefore: "2025-04-01T00:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids(result.Requests))
+
+	_, result, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{CreatedBefore: "2025-04-01T00:00:00Z", Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids(result.Requests))
+	assert.Empty(t, query.Get("limit"))
+
+	_, result, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, result.Requests, 4)
+	assert.Equal(t, "2", query.Get("limit"))
+
+	_, _, err = cred.ListRequests(context.Background(), &mcp.CallToolRequest{}, ListRequestsCmd{CreatedAfter: "yesterday"})
+	assert.Error(t, err)
+}
+
 func TestGetRequest(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		actualURL, err := url.Parse(r.URL.String())
@@ -155,3 +258,251 @@ func TestGetRequest_Error(t *testing.T) {
 	_, _, err := cred.GetRequest(context.Background(), &mcp.CallToolRequest{}, GetRequestCmd{Id: "123"})
 	assert.Error(t, err)
 }
+
+func TestCreateMaintenanceRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/request", r.URL.Path)
+		assert.Equal(t, "create", r.URL.Query().Get("cmd"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var sent Request
+		assert.NoError(t, xml.Unmarshal(body, &sent))
+		assert.Len(t, sent.Actions, 1)
+		assert.Equal(t, "maintenance_incident", sent.Actions[0].Type)
+		assert.Equal(t, "home:testuser:fix", sent.Actions[0].Source.Project)
+		assert.Equal(t, "testpackage", sent.Actions[0].Source.Package)
+		assert.Equal(t, "openSUSE:Maintenance", sent.Actions[0].Target.Project)
+		assert.Equal(t, "openSUSE:Leap:15.6:Update", sent.Actions[0].Target.ReleaseProject)
+		assert.Equal(t, "Fix CVE", sent.Description)
+		assert.NotContains(t, string(body), "rev=")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<request id="4711" creator="testuser"><state name="new"/></request>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.CreateMaintenanceRequest(context.Background(), &mcp.CallToolRequest{}, CreateMaintenanceRequestCmd{
+		SourceProject:  "home:testuser:fix",
+		SourcePackage:  "testpackage",
+		TargetProject:  "openSUSE:Maintenance",
+		ReleaseProject: "openSUSE:Leap:15.6:Update",
+		Description:    "Fix CVE",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "4711", result.ID)
+	assert.Equal(t, "new", result.State)
+}
+
+func TestCreateSubmitRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/request", r.URL.Path)
+		assert.Equal(t, "create", r.URL.Query().Get("cmd"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var sent Request
+		assert.NoError(t, xml.Unmarshal(body, &sent))
+		assert.Len(t, sent.Actions, 1)
+		assert.Equal(t, "submit", sent.Actions[0].Type)
+		assert.Equal(t, "home:testuser:branches:devel:tools", sent.Actions[0].Source.Project)
+		assert.Equal(t, "testpackage", sent.Actions[0].Source.Package)
+		assert.Equal(t, "3", sent.Actions[0].Source.Rev)
+		assert.Equal(t, "devel:tools", sent.Actions[0].Target.Project)
+		assert.Equal(t, "testpackage", sent.Actions[0].Target.Package)
+		assert.Equal(t, "Update to 1.2", sent.Description)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `<request id="4712" creator="testuser"><state name="review"/></request>`)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.CreateSubmitRequest(context.Background(), &mcp.CallToolRequest{}, CreateSubmitRequestCmd{
+		SourceProject: "home:testuser:branches:devel:tools",
+		SourcePackage: "testpackage",
+		TargetProject: "devel:tools",
+		Revision:      "3",
+		Description:   "Update to 1.2",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "4712", result.ID)
+	assert.Equal(t, "review", result.State)
+}
+
+func TestSetReviewState(t *testing.T) {
+	accepted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/request/123", r.URL.Path)
+		if r.Method == "POST" {
+			assert.Equal(t, "changereviewstate", r.URL.Query().Get("cmd"))
+			assert.Equal(t, "accepted", r.URL.Query().Get("newstate"))
+			assert.Equal(t, "factory-staging", r.URL.Query().Get("by_group"))
+			assert.Empty(t, r.URL.Query().Get("by_user"))
+			assert.Equal(t, "looks good", r.URL.Query().Get("comment"))
+			accepted = true
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<status code="ok"/>`)
+			return
+		}
+		state := "new"
+		if accepted {
+			state = "accepted"
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `<request id="123"><state name="review"/><review state="%s" by_group="factory-staging"/></request>`, state)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.SetReviewState(context.Background(), &mcp.CallToolRequest{}, SetReviewStateCmd{
+		Id:      "123",
+		State:   "accepted",
+		ByGroup: "factory-staging",
+		Comment: "looks good",
+	})
+	assert.NoError(t, err)
+	assert.True(t, accepted)
+	assert.Len(t, result.Reviews, 1)
+	assert.Equal(t, "accepted", result.Reviews[0].State)
+
+	_, _, err = cred.SetReviewState(context.Background(), &mcp.CallToolRequest{}, SetReviewStateCmd{
+		Id:     "123",
+		State:  "accepted",
+		ByUser: "someone",
+	})
+	assert.Error(t, err)
+
+	_, _, err = cred.SetReviewState(context.Background(), &mcp.CallToolRequest{}, SetReviewStateCmd{
+		Id:      "123",
+		State:   "accepted",
+		ByUser:  "testuser",
+		ByGroup: "factory-staging",
+	})
+	assert.Error(t, err)
+}
+
+func TestResolveSupersedeChain(t *testing.T) {
+	states := map[string]string{
+		"/request/1": `<request id="1"><state name="superseded" superseded_by="2"/></request>`,
+		"/request/2": `<request id="2"><state name="superseded" superseded_by="3"/></request>`,
+		"/request/3": `<request id="3"><state name="review"/></request>`,
+		"/request/4": `<request id="4"><state name="superseded" superseded_by="5"/></request>`,
+		"/request/5": `<request id="5"><state name="superseded" superseded_by="4"/></request>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := states[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{
+		Name:    "testuser",
+		Passwd:  "testpassword",
+		Apiaddr: server.URL,
+	}
+
+	_, result, err := cred.ResolveSupersedeChain(context.Background(), &mcp.CallToolRequest{}, ResolveSupersedeChainCmd{Id: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result.Current)
+	assert.False(t, result.Cycle)
+	assert.Len(t, result.Chain, 3)
+	assert.Equal(t, "2", result.Chain[0].SupersededBy)
+	assert.Equal(t, "review", result.Chain[2].State)
+
+	_, result, err = cred.ResolveSupersedeChain(context.Background(), &mcp.CallToolRequest{}, ResolveSupersedeChainCmd{Id: "4"})
+	assert.NoError(t, err)
+	assert.True(t, result.Cycle)
+	assert.Len(t, result.Chain, 2)
+}
+
+func TestGetRequestActionDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/request/42":
+			fmt.Fprint(w, `<request id="42" creator="testuser">
+  <action type="submit">
+    <source project="home:testuser" package="foo" rev="3"/>
+    <target project="openSUSE:Factory" package="foo"/>
+  </action>
+  <action type="delete">
+    <target project="openSUSE:Factory" package="bar"/>
+  </action>
+  <state name="review"/>
+</request>`)
+		case r.Method == "POST" && r.URL.Path == "/source/home:testuser/foo":
+			query := r.URL.Query()
+			assert.Equal(t, "diff", query.Get("cmd"))
+			assert.Equal(t, "openSUSE:Factory", query.Get("oproject"))
+			assert.Equal(t, "foo", query.Get("opackage"))
+			assert.Equal(t, "3", query.Get("rev"))
+			fmt.Fprint(w, "--- foo.spec\n+++ foo.spec\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.GetRequestActionDiff(context.Background(), nil, GetRequestActionDiffCmd{Id: "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.NrActions)
+	assert.Equal(t, "--- foo.spec\n+++ foo.spec\n", result.Diff)
+
+	_, result, err = cred.GetRequestActionDiff(context.Background(), nil, GetRequestActionDiffCmd{Id: "42", ActionIndex: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Diff)
+	assert.NotEmpty(t, result.Note)
+
+	_, _, err = cred.GetRequestActionDiff(context.Background(), nil, GetRequestActionDiffCmd{Id: "42", ActionIndex: 2})
+	assert.Error(t, err)
+}
+
+func TestChangeRequestState(t *testing.T) {
+	state := "new"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/request/123", r.URL.Path)
+		if r.Method == "POST" {
+			assert.Equal(t, "changestate", r.URL.Query().Get("cmd"))
+			assert.Equal(t, "declined", r.URL.Query().Get("newstate"))
+			assert.Equal(t, "does not build", r.URL.Query().Get("comment"))
+			state = "declined"
+			fmt.Fprint(w, `<status code="ok"/>`)
+			return
+		}
+		fmt.Fprintf(w, `<request id="123"><state name="%s" who="testuser"/></request>`, state)
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.ChangeRequestState(context.Background(), nil, ChangeRequestStateCmd{
+		Id:      "123",
+		State:   "declined",
+		Comment: "does not build",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "declined", result.State.Name)
+
+	_, _, err = cred.ChangeRequestState(context.Background(), nil, ChangeRequestStateCmd{Id: "123", State: "superseded"})
+	assert.Error(t, err)
+}
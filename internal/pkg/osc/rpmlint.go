@@ -0,0 +1,89 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/openSUSE/osc-mcp/internal/pkg/buildlog"
+)
+
+type RunRpmlintParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	BundleName  string `json:"bundle_name" jsonschema:"Name of the source package or bundle."`
+	Path        string `json:"path" jsonschema:"RPM or spec file to check, e.g. a package of the build result. Relative paths are relative to the bundle directory."`
+	Config      string `json:"config,omitempty" jsonschema:"rpmlintrc file with filters. Defaults to the rpmlintrc of the bundle if it has one."`
+}
+
+type RunRpmlintResult struct {
+	Findings []buildlog.LintFinding `json:"findings"`
+	Errors   int                    `json:"errors"`
+	Warnings int                    `json:"warnings"`
+	Output   string                 `json:"output"`
+}
+
+func (cred *OSCCredentials) RunRpmlint(ctx context.Context, req *mcp.CallToolRequest, params RunRpmlintParam) (*mcp.CallToolResult, *RunRpmlintResult, error) {
+	slog.Debug("mcp tool call: RunRpmlint", "params", params)
+	if params.Path == "" {
+		return nil, nil, fmt.Errorf("path must be specified")
+	}
+	rpmlint, err := exec.LookPath("rpmlint")
+	if err != nil {
+		return nil, nil, fmt.Errorf("rpmlint is not installed, install it with 'zypper install rpmlint'")
+	}
+	bundleDir := filepath.Join(cred.TempDir, params.ProjectName, params.BundleName)
+	path := params.Path
+	if !filepath.IsAbs(path) {
+		if params.ProjectName == "" || params.BundleName == "" {
+			return nil, nil, fmt.Errorf("project and bundle name must be specified for a relative path")
+		}
+		path = filepath.Join(bundleDir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	config := params.Config
+	if config == "" && params.ProjectName != "" && params.BundleName != "" {
+		matches, _ := filepath.Glob(filepath.Join(bundleDir, "*rpmlintrc"))
+		if len(matches) > 0 {
+			config = matches[0]
+		}
+	} else if config != "" && !filepath.IsAbs(config) {
+		config = filepath.Join(bundleDir, config)
+	}
+	args := []string{}
+	if config != "" {
+		args = append(args, "-r", config)
+	}
+	args = append(args, path)
+
+	cmd := exec.CommandContext(ctx, rpmlint, args...)
+	slog.Debug("running rpmlint", "command", cmd.String())
+	output, err := cmd.CombinedOutput()
+	// rpmlint has a non zero exit status if it found errors
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, nil, fmt.Errorf("failed to run '%s': %w", cmd.String(), err)
+	}
+
+	result := &RunRpmlintResult{
+		Findings: buildlog.ParseRpmLint(strings.Split(string(output), "\n")),
+		Output:   string(output),
+	}
+	for _, finding := range result.Findings {
+		switch finding.Severity {
+		case "E":
+			result.Errors++
+		case "W":
+			result.Warnings++
+		}
+	}
+	return nil, result, nil
+}
@@ -0,0 +1,136 @@
+package osc
+
+import (
+	"strconv"
+	"strings"
+)
+
+func isAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// rpmVerCmp compares two version or release strings with the semantics of
+// rpmvercmp. It returns 1 if a is newer, -1 if b is newer and 0 if they are
+// equal.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	for len(a) > 0 || len(b) > 0 {
+		for len(a) > 0 && !isAlnum(a[0]) && a[0] != '~' && a[0] != '^' {
+			a = a[1:]
+		}
+		for len(b) > 0 && !isAlnum(b[0]) && b[0] != '~' && b[0] != '^' {
+			b = b[1:]
+		}
+		// a tilde sorts before everything, even the end of the string
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		// a caret sorts after the end of the string, but before everything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if a == "" || b == "" {
+			break
+		}
+
+		isNum := isDigit(a[0])
+		segEnd := func(s string) int {
+			i := 0
+			for i < len(s) && isAlnum(s[i]) && isDigit(s[i]) == isNum {
+				i++
+			}
+			return i
+		}
+		i, j := segEnd(a), segEnd(b)
+		segA, segB := a[:i], b[:j]
+		a, b = a[i:], b[j:]
+		if segB == "" {
+			// numeric segments are newer than alpha segments
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+		if isNum {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+	if a == "" && b == "" {
+		return 0
+	}
+	if a == "" {
+		return -1
+	}
+	return 1
+}
+
+// splitEVR splits a string of the form [epoch:]version[-release].
+func splitEVR(evr string) (epoch int, version, release string) {
+	if i := strings.Index(evr, ":"); i > 0 {
+		if e, err := strconv.Atoi(evr[:i]); err == nil {
+			epoch = e
+			evr = evr[i+1:]
+		}
+	}
+	version = evr
+	if i := strings.LastIndex(evr, "-"); i >= 0 {
+		version, release = evr[:i], evr[i+1:]
+	}
+	return epoch, version, release
+}
+
+// compareEVR compares two [epoch:]version[-release] strings. The release is
+// only compared if both strings have one, so that 1.2-3 satisfies 1.2.
+func compareEVR(a, b string) int {
+	epochA, versionA, releaseA := splitEVR(a)
+	epochB, versionB, releaseB := splitEVR(b)
+	if epochA != epochB {
+		if epochA > epochB {
+			return 1
+		}
+		return -1
+	}
+	if c := rpmVerCmp(versionA, versionB); c != 0 {
+		return c
+	}
+	if releaseA == "" || releaseB == "" {
+		return 0
+	}
+	return rpmVerCmp(releaseA, releaseB)
+}
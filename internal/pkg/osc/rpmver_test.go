@@ -0,0 +1,106 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRpmVerCmp(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"1.10", "1.9", 1},
+		{"1.05", "1.5", 0},
+		{"1.0a", "1.0", 1},
+		{"1.0", "1.0.1", -1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.0.1", -1},
+		{"a", "1", -1},
+		{"1.0_1", "1.0.1", 0},
+		{"1.2.3", "1.2.10", -1},
+		{"1.0~rc1", "1.0~beta", 1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~rc1", "0.9", 1},
+		{"1.0.0~rc1", "1.0", 1},
+	}
+	for _, tc := range testCases {
+		if got := rpmVerCmp(tc.a, tc.b); got != tc.expected {
+			t.Errorf("rpmVerCmp(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
+
+func TestCompareEVR(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2-3", "1.2", 0},
+		{"1.2-3", "1.2-4", -1},
+		{"1:1.0-1", "2.0-1", 1},
+		{"2.31-150400.1.1", "2.30", 1},
+		{"1.1.0-150400.1.1", "1.11", -1},
+	}
+	for _, tc := range testCases {
+		if got := compareEVR(tc.a, tc.b); got != tc.expected {
+			t.Errorf("compareEVR(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
+
+func TestFilterMinVersion(t *testing.T) {
+	packages := []rpm_pack{
+		{Name: "foo", Version: "1.0-1.1", Arch: "x86_64"},
+		{Name: "foo", Version: "1.2-1.1", Arch: "x86_64"},
+	}
+	result := filterMinVersion(packages, "1.1")
+	if len(result) != 1 || result[0].Version != "1.2-1.1" || !*result[0].Satisfies {
+		t.Errorf("unexpected result %+v", result)
+	}
+	result = filterMinVersion(packages, "2.0")
+	if len(result) != 2 || *result[0].Satisfies || *result[1].Satisfies {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestSortPackages(t *testing.T) {
+	packages := []rpm_pack{
+		{Name: "foo", Version: "1.2.3-1.1", Arch: "x86_64"},
+		{Name: "bar", Version: "1.0-1.1", Arch: "x86_64"},
+		{Name: "foo", Version: "1.2.10-1.1", Arch: "x86_64"},
+		{Name: "foo", Version: "1.2.10-1.1", Arch: "aarch64"},
+		{Name: "foo", Version: "2.0~rc1-1.1", Arch: "x86_64"},
+		{Name: "foo", Version: "0.9-1.1", Arch: "x86_64", Epoch: "1"},
+	}
+	sortPackages(packages)
+	order := []string{}
+	for _, p := range packages {
+		order = append(order, p.Name+"-"+p.evr()+"."+p.Arch)
+	}
+	expected := []string{
+		"bar-1.0-1.1.x86_64",
+		"foo-1:0.9-1.1.x86_64",
+		"foo-2.0~rc1-1.1.x86_64",
+		"foo-1.2.10-1.1.x86_64",
+		"foo-1.2.10-1.1.aarch64",
+		"foo-1.2.3-1.1.x86_64",
+	}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v but got %v", expected, order)
+	}
+
+	latest := latestPackages(packages[2:])
+	if len(latest) != 1 || latest[0].Version != "2.0~rc1-1.1" {
+		t.Errorf("unexpected latest packages %+v", latest)
+	}
+	latest = latestPackages(packages[3:])
+	if len(latest) != 2 || latest[0].Arch != "x86_64" || latest[1].Arch != "aarch64" {
+		t.Errorf("expected both architectures of the latest version, got %+v", latest)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -11,10 +12,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
-	"unicode"
+	"time"
 
 	"github.com/beevik/etree"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,10 +25,17 @@ import (
 
 type SearchSrcBundleParam struct {
 	// mcp.Meta
-	Name     string   `json:"package_name,omitempty" jsonschema:"Name of the source package to search"`
-	Projects []string `json:"projects,omitempty" jsonschema:"Optional list of projects to search in"`
+	Name       string   `json:"package_name,omitempty" jsonschema:"Name of the source package to search"`
+	Projects   []string `json:"projects,omitempty" jsonschema:"Optional list of projects to search in"`
+	Maintainer string   `json:"maintainer,omitempty" jsonschema:"Only return bundles which have this user as maintainer"`
+	Limit      int      `json:"limit,omitempty" jsonschema:"Maximum number of returned bundles, defaults to 100."`
+	Offset     int      `json:"offset,omitempty" jsonschema:"Number of bundles to skip, use it with limit to page through the results."`
 }
 
+// defaultSearchLimit is the number of bundles returned by a search if no
+// limit is given
+const defaultSearchLimit = 100
+
 func (p SearchSrcBundleParam) GetMeta() map[string]any {
 	return nil
 }
@@ -33,14 +43,32 @@ func (p SearchSrcBundleParam) GetMeta() map[string]any {
 func (p SearchSrcBundleParam) SetMeta(meta map[string]any) {}
 
 type BundleInfo struct {
-	Name        string `json:"name"`
-	Project     string `json:"project"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Name         string `json:"name"`
+	Project      string `json:"project"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	DevelProject string `json:"devel_project,omitempty" jsonschema:"Project in which the bundle is developed, changes should be submitted there"`
+	WebURL       string `json:"web_url,omitempty"`
 }
 
 type BundleOut struct {
-	Result []BundleInfo `json:"result" jsonschema:"List of found bundles."`
+	Result       []BundleInfo `json:"result" jsonschema:"List of found bundles."`
+	TotalMatches int          `json:"total_matches,omitempty" jsonschema:"Number of all found bundles, if the result is limited."`
+}
+
+// pageBundles returns at most limit bundles starting at offset.
+func pageBundles(bundles []BundleInfo, limit, offset int) []BundleInfo {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if offset < 0 || offset > len(bundles) {
+		offset = len(bundles)
+	}
+	end := offset + limit
+	if end > len(bundles) {
+		end = len(bundles)
+	}
+	return bundles[offset:end]
 }
 
 func listLocalPackages(path string, packageName string) ([]BundleInfo, error) {
@@ -66,20 +94,28 @@ func listLocalPackages(path string, packageName string) ([]BundleInfo, error) {
 	return bundles, nil
 }
 
-func (cred OSCCredentials) searchRemoteSrcBundle(ctx context.Context, bundleName string, projects []string) ([]BundleInfo, error) {
+// srcBundleMatch returns the xpath predicate of the package search for the
+// name, the projects and the maintainer of params.
+func srcBundleMatch(params SearchSrcBundleParam) string {
 	var matches []string
-	if bundleName != "" {
-		matches = append(matches, fmt.Sprintf("@name='%s'", bundleName))
+	if params.Name != "" {
+		matches = append(matches, fmt.Sprintf("@name='%s'", params.Name))
 	}
-	if len(projects) > 0 {
+	if len(params.Projects) > 0 {
 		var projectMatches []string
-		for _, p := range projects {
+		for _, p := range params.Projects {
 			projectMatches = append(projectMatches, fmt.Sprintf("@project='%s'", p))
 		}
 		matches = append(matches, fmt.Sprintf("(%s)", strings.Join(projectMatches, " or ")))
 	}
-	match := strings.Join(matches, " and ")
+	if params.Maintainer != "" {
+		matches = append(matches, fmt.Sprintf("person[@userid='%s' and @role='maintainer']", params.Maintainer))
+	}
+	return strings.Join(matches, " and ")
+}
 
+// searchPackageMatch returns the packages matching the xpath expression match.
+func (cred OSCCredentials) searchPackageMatch(ctx context.Context, match string) ([]BundleInfo, error) {
 	apiURL, err := url.Parse(fmt.Sprintf("%s/search/package", cred.GetAPiAddr()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse API URL: %w", err)
@@ -94,11 +130,10 @@ func (cred OSCCredentials) searchRemoteSrcBundle(ctx context.Context, bundleName
 	}
 
 	httpReq.Header.Set("User-Agent", "osc-mcp")
-	httpReq.SetBasicAuth(cred.Name, cred.Passwd)
+	cred.setAuth(httpReq)
 	httpReq.Header.Set("Accept", "application/xml; charset=utf-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := cred.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -125,28 +160,43 @@ func (cred OSCCredentials) searchRemoteSrcBundle(ctx context.Context, bundleName
 		if description := pkg.SelectElement("description"); description != nil {
 			p.Description = description.Text()
 		}
+		if devel := pkg.SelectElement("devel"); devel != nil {
+			p.DevelProject = devel.SelectAttrValue("project", "")
+		}
+		if p.Name != "" && p.Project != "" {
+			p.WebURL = fmt.Sprintf("%s/package/show/%s/%s", cred.GetWebAddr(), p.Project, p.Name)
+		}
 		packages = append(packages, p)
 	}
 	return packages, nil
 }
 
+// isLocalSearch returns true if the local packages should be listed. This is
+// the case if the only project is 'local' or if neither a project nor a name
+// is given. A name alone, even 'local', always searches remotely.
+func isLocalSearch(params SearchSrcBundleParam) bool {
+	if len(params.Projects) == 1 && strings.EqualFold(strings.TrimSpace(params.Projects[0]), "local") {
+		return true
+	}
+	return len(params.Projects) == 0 && params.Name == "" && params.Maintainer == ""
+}
+
 // func (cred OSCCredentials) SearchSrcBundle(ctx context.Context, req *mcp.CallToolRequest, params SearchSrcBundleParam) (*mcp.CallToolResult, any, error) {
 func (cred OSCCredentials) SearchSrcBundle(ctx context.Context, req *mcp.CallToolRequest, params SearchSrcBundleParam) (*mcp.CallToolResult, *BundleOut, error) {
 	slog.Debug("mcp tool call: SearchSrcBundle", "session", req.Session.ID(), "params", params)
-	isLocal := false
-	if len(params.Projects) == 1 && strings.EqualFold("local", strings.ToLower(params.Projects[0])) || (len(params.Projects) == 0 && params.Name == "") {
-		isLocal = true
+	if params.Offset < 0 {
+		return nil, nil, fmt.Errorf("offset must not be negative")
 	}
-	if isLocal {
+	if isLocalSearch(params) {
 		var bundles []BundleInfo
 		bundles, err := listLocalPackages(cred.TempDir, params.Name)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to list local packages in '%s': %w", cred.TempDir, err)
 		}
-		return nil, &BundleOut{Result: bundles}, nil
+		return nil, &BundleOut{Result: pageBundles(bundles, params.Limit, params.Offset), TotalMatches: len(bundles)}, nil
 	}
 
-	packages, err := cred.searchRemoteSrcBundle(ctx, params.Name, params.Projects)
+	packages, err := cred.searchPackageMatch(ctx, srcBundleMatch(params))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -155,71 +205,261 @@ func (cred OSCCredentials) SearchSrcBundle(ctx context.Context, req *mcp.CallToo
 	}
 
 	return nil, &BundleOut{
-		Result: packages,
+		Result:       pageBundles(packages, params.Limit, params.Offset),
+		TotalMatches: len(packages),
 	}, nil
 }
 
+type FindLinkingPackagesParam struct {
+	ProjectName string   `json:"project_name" jsonschema:"Project of the linked package"`
+	PackageName string   `json:"package_name" jsonschema:"Name of the linked package"`
+	Projects    []string `json:"projects,omitempty" jsonschema:"Optional list of projects to search for linking packages. Defaults to the whole instance."`
+}
+
+// linkingMatch returns the xpath expression for packages which link to the
+// given package, optionally restricted to some projects.
+func linkingMatch(projectName, packageName string, projects []string) string {
+	match := fmt.Sprintf("linkinfo/@project='%s' and linkinfo/@package='%s'", projectName, packageName)
+	if len(projects) > 0 {
+		var projectMatches []string
+		for _, p := range projects {
+			projectMatches = append(projectMatches, fmt.Sprintf("@project='%s'", p))
+		}
+		match = fmt.Sprintf("%s and (%s)", match, strings.Join(projectMatches, " or "))
+	}
+	return match
+}
+
+func (cred OSCCredentials) FindLinkingPackages(ctx context.Context, req *mcp.CallToolRequest, params FindLinkingPackagesParam) (*mcp.CallToolResult, *BundleOut, error) {
+	slog.Debug("mcp tool call: FindLinkingPackages", "params", params)
+	if params.ProjectName == "" || params.PackageName == "" {
+		return nil, nil, fmt.Errorf("project and package name must be specified")
+	}
+	packages, err := cred.searchPackageMatch(ctx, linkingMatch(params.ProjectName, params.PackageName, params.Projects))
+	if err != nil {
+		return nil, nil, err
+	}
+	if packages == nil {
+		packages = []BundleInfo{}
+	}
+	return nil, &BundleOut{Result: packages}, nil
+}
+
 type SearchPackagesParams struct {
 	mcp.Meta
-	Path            string `json:"path" jsonschema:"Distribution to serach in. Underscores are replaced with colons openSUSE_Tumbleweed is openSUSE:Tumbleweed."`
-	Path_repository string `json:"path_repository" jsonschema:"Different build type. Use standard for most cases."`
-	Arch            string `json:"arch,omitempty"`
-	Pattern         string `json:"pattern" jsonschema:"package name to search for, matches any package for which pattern is substring."`
-	ExactMatch      bool   `json:"exact,omitempty" jsonschema:"treat pattern as exact match"`
-	Regexp          bool   `json:"regexp,omitempty" jsonschema:"treat pattern as regexp"`
+	Path            string   `json:"path" jsonschema:"Distribution to serach in. Underscores are replaced with colons openSUSE_Tumbleweed is openSUSE:Tumbleweed."`
+	Path_repository string   `json:"path_repository" jsonschema:"Different build type. Use standard for most cases."`
+	Arch            string   `json:"arch,omitempty" jsonschema:"Only return packages for this architecture and noarch packages."`
+	Arches          []string `json:"arches,omitempty" jsonschema:"Only return packages for these architectures and noarch packages, e.g. x86_64 and aarch64 to check that a package exists for both."`
+	Pattern         string   `json:"pattern" jsonschema:"package name to search for, matches any package for which pattern is substring."`
+	ExactMatch      bool     `json:"exact,omitempty" jsonschema:"treat pattern as exact match"`
+	Regexp          bool     `json:"regexp,omitempty" jsonschema:"treat pattern as regexp"`
+	MinVersion      string   `json:"min_version,omitempty" jsonschema:"Only return packages with at least this version, given as [epoch:]version[-release]. If no package satisfies the version, all matching packages are returned."`
+	LatestOnly      bool     `json:"latest_only,omitempty" jsonschema:"Only return the highest version of every package."`
+	MatchProvides   bool     `json:"match_provides,omitempty" jsonschema:"Match the pattern against the capabilities the packages provide, e.g. libfoo.so.1 or pkgconfig(foo), instead of the package names. Downloads the much bigger repository metadata."`
 }
 
 type SearchPackagesResult struct {
 	Packages []rpm_pack `json:"packages"`
+	Message  string     `json:"message,omitempty"`
 }
 
+var ErrIndexNotFound = errors.New("repository index not found")
+
 type rpm_pack struct {
-	Name    string
-	Arch    string
-	Version string
+	Name       string
+	Arch       string
+	Version    string
+	Epoch      string   `json:",omitempty"`
+	Repository string   `json:",omitempty"`
+	Provides   []string `json:",omitempty"`
+	Satisfies  *bool    `json:",omitempty"`
 }
 
+// The epoch can either prefix the whole file name as in 1:foo-2.0-1.x86_64.rpm
+// or the version as in foo-1:2.0-1.x86_64.rpm. The version has to start with
+// a digit and the release has to contain one, everything in front of them is
+// part of the name.
+var (
+	rpmNameVersionRelease = regexp.MustCompile(`^(?:(\d+):)?(.+)-(?:(\d+):)?(\d[^-]*-[^-]*\d[^-]*)\.([^.-]+)\.rpm$`)
+	rpmNameVersion        = regexp.MustCompile(`^(?:(\d+):)?(.+)-(?:(\d+):)?(\d[^-]*)\.([^.-]+)\.rpm$`)
+	rpmName               = regexp.MustCompile(`^(?:(\d+):)?(.+)\.([^.-]+)\.rpm$`)
+)
+
 // parseRPMFileName extracts the package name from an RPM filename.
 // e.g. "pkg-name-1.2.3-1.x86_64.rpm" -> "rpm_pack {Name: pkg-name, Version 1.2.3-1, Arch: x86_64}"
 func parseRPMFileName(filename string) rpm_pack {
-	if !strings.HasSuffix(filename, ".rpm") {
-		return rpm_pack{}
+	for _, re := range []*regexp.Regexp{rpmNameVersionRelease, rpmNameVersion} {
+		if m := re.FindStringSubmatch(filename); m != nil {
+			epoch := m[1]
+			if epoch == "" {
+				epoch = m[3]
+			}
+			return rpm_pack{Name: m[2], Arch: m[5], Version: m[4], Epoch: epoch}
+		}
+	}
+	if m := rpmName.FindStringSubmatch(filename); m != nil {
+		return rpm_pack{Name: m[2], Arch: m[3], Epoch: m[1]}
 	}
-	workstring := filename[:len(filename)-4]
+	return rpm_pack{}
+}
 
-	lastDot := strings.LastIndex(workstring, ".")
-	if lastDot == -1 {
-		return rpm_pack{}
+// nameMatcher returns the function which matches a name against the
+// pattern of params.
+func nameMatcher(params SearchPackagesParams) (func(string) bool, error) {
+	switch {
+	case params.Pattern == "":
+		return func(string) bool { return true }, nil
+	case params.Regexp:
+		re, err := regexp.Compile(params.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp pattern: %w", err)
+		}
+		return re.MatchString, nil
+	case params.ExactMatch:
+		return func(name string) bool { return name == params.Pattern }, nil
+	default:
+		return func(name string) bool { return strings.Contains(name, params.Pattern) }, nil
 	}
-	arch := workstring[lastDot+1:]
-	workstring = workstring[:lastDot] // <name>-<version>-<release>
+}
 
-	releaseDash := strings.LastIndex(workstring, "-")
-	if releaseDash == -1 {
-		return rpm_pack{Name: workstring, Arch: arch}
+// archMatcher returns the function which checks if an architecture was
+// requested by params. noarch packages are always matched.
+func archMatcher(params SearchPackagesParams) func(string) bool {
+	arches := map[string]bool{}
+	for _, arch := range params.Arches {
+		arches[arch] = true
+	}
+	if params.Arch != "" {
+		arches[params.Arch] = true
 	}
+	return func(arch string) bool {
+		return len(arches) == 0 || arches[arch] || arch == "noarch"
+	}
+}
+
+// matchIndex returns the packages of a repository index which match the
+// pattern and the architectures of params. The index of a repository holds the
+// packages of all its architectures, so the packages are tagged with the
+// directory of the repository they are in. Packages which are listed more
+// than once are only returned once.
+func matchIndex(index io.Reader, repoPath string, params SearchPackagesParams, match func(string) bool) ([]rpm_pack, error) {
+	archMatch := archMatcher(params)
+	packages := []rpm_pack{}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(index)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasSuffix(line, ".rpm") {
+			continue
+		}
+		rpmFile := filepath.Base(line)
+
+		actualPackage := parseRPMFileName(rpmFile)
+		if actualPackage.Name == "" || !archMatch(actualPackage.Arch) {
+			continue
+		}
 
-	// Heuristic: if the "release" part contains no digits, it's part of the name
-	release := workstring[releaseDash+1:]
-	if !strings.ContainsAny(release, "0123456789") {
-		return rpm_pack{Name: workstring, Arch: arch}
+		key := actualPackage.Name + "-" + actualPackage.Version + "." + actualPackage.Arch
+		if match(actualPackage.Name) && !seen[key] {
+			seen[key] = true
+			actualPackage.Repository = path.Join(repoPath, path.Dir(strings.TrimPrefix(line, "./")))
+			packages = append(packages, actualPackage)
+		}
 	}
 
-	versionCand := workstring[:releaseDash]
-	versionDash := strings.LastIndex(versionCand, "-")
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading gzipped index: %w", err)
+	}
+	return packages, nil
+}
 
-	if versionDash == -1 {
-		return rpm_pack{Name: versionCand, Arch: arch, Version: release}
+// searchIndex searches the package names of the INDEX.gz of a repository.
+func (cred OSCCredentials) searchIndex(ctx context.Context, repoURL, cacheDir, repoPath string, params SearchPackagesParams, match func(string) bool) ([]rpm_pack, error) {
+	cacheFile := indexCacheFile(cacheDir, repoPath, "INDEX.gz")
+	if err := cred.fetchIndex(ctx, repoURL+"/INDEX.gz", cacheFile); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(cacheFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file: %w", err)
 	}
+	defer f.Close()
 
-	// Heuristic: if the "version" part does not start with a digit, it's part of the name
-	version := versionCand[versionDash+1:]
-	if len(version) == 0 || !unicode.IsDigit(rune(version[0])) {
-		return rpm_pack{Name: versionCand, Arch: arch, Version: release}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 	}
+	defer gz.Close()
+	return matchIndex(gz, repoPath, params, match)
+}
 
-	name := versionCand[:versionDash]
-	return rpm_pack{Name: name, Arch: arch, Version: version + "-" + release}
+// indexCacheFile returns the cache file of a file of a repository.
+func indexCacheFile(cacheDir, repoPath, name string) string {
+	return filepath.Join(cacheDir, strings.ReplaceAll(path.Join(repoPath, name), "/", "_"))
+}
+
+// defaultIndexTTL is the time after which a cached INDEX.gz is revalidated
+const defaultIndexTTL = time.Hour
+
+// fetchIndex downloads the index of a repository to cacheFile. A cached index
+// is used as long as it is younger than cred.IndexTTL, after that it is
+// revalidated with If-Modified-Since and only downloaded again if it changed.
+func (cred OSCCredentials) fetchIndex(ctx context.Context, downloadURL, cacheFile string) error {
+	ttl := cred.IndexTTL
+	if ttl <= 0 {
+		ttl = defaultIndexTTL
+	}
+	info, err := os.Stat(cacheFile)
+	if err == nil && time.Since(info.ModTime()) < ttl {
+		return nil
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	slog.Debug("downloading", "url", downloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if info != nil {
+		httpReq.Header.Set("If-Modified-Since", info.ModTime().UTC().Format(http.TimeFormat))
+	}
+
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && info != nil:
+		now := time.Now()
+		if err := os.Chtimes(cacheFile, now, now); err != nil {
+			return fmt.Errorf("failed to update cache file: %w", err)
+		}
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrIndexNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("download failed with status: %s", resp.Status)
+	}
+
+	// write to a temporary file first, so that a failed download doesn't
+	// leave a truncated index in the cache
+	f, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write to cache file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write to cache file: %w", err)
+	}
+	if err := os.Rename(f.Name(), cacheFile); err != nil {
+		return fmt.Errorf("failed to write to cache file: %w", err)
+	}
+	return nil
 }
 
 func (cred OSCCredentials) SearchPackages(ctx context.Context, req *mcp.CallToolRequest, params SearchPackagesParams) (*mcp.CallToolResult, any, error) {
@@ -237,7 +477,7 @@ func (cred OSCCredentials) SearchPackages(ctx context.Context, req *mcp.CallTool
 		repoPath = repoPath + "/" + params.Path_repository
 	}
 
-	downloadURL, err := url.Parse(fmt.Sprintf("https://%s%s/INDEX.gz", apiaddr, repoPath))
+	repoURL, err := url.Parse(fmt.Sprintf("https://%s%s", apiaddr, repoPath))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse download URL: %w", err)
 	}
@@ -247,96 +487,82 @@ func (cred OSCCredentials) SearchPackages(ctx context.Context, req *mcp.CallTool
 		return nil, nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	cacheKey := strings.ReplaceAll(downloadURL.Path, "/", "_")
-	cacheFile := filepath.Join(cacheDir, cacheKey)
-
-	if _, err := os.Stat(cacheFile); os.IsNotExist(err) {
-		httpReq, err := http.NewRequestWithContext(ctx, "GET", downloadURL.String(), nil)
-		slog.Debug("downloading", "url", downloadURL.String())
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		client := &http.Client{}
-		resp, err := client.Do(httpReq)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to execute request: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, nil, fmt.Errorf("download failed with status: %s", resp.Status)
-		}
-
-		f, err := os.Create(cacheFile)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create cache file: %w", err)
-		}
-		if _, err := io.Copy(f, resp.Body); err != nil {
-			f.Close()
-			return nil, nil, fmt.Errorf("failed to write to cache file: %w", err)
-		}
-		f.Close()
-	}
-
-	f, err := os.Open(cacheFile)
+	match, err := nameMatcher(params)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open cache file: %w", err)
+		return nil, nil, err
 	}
-	defer f.Close()
 
-	gz, err := gzip.NewReader(f)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	var packages []rpm_pack
+	if params.MatchProvides {
+		packages, err = cred.searchProvides(ctx, repoURL.String(), cacheDir, repoPath, params, match)
+	} else {
+		packages, err = cred.searchIndex(ctx, repoURL.String(), cacheDir, repoPath, params, match)
 	}
-	defer gz.Close()
+	if errors.Is(err, ErrIndexNotFound) {
+		return nil, SearchPackagesResult{
+			Packages: []rpm_pack{},
+			Message: fmt.Sprintf("the repository %s has no published binaries yet, check the build status of the project %s or search in a different repository",
+				strings.TrimPrefix(repoPath, "/repositories/"), params.Path),
+		}, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	result := SearchPackagesResult{Packages: packages}
+	if params.MinVersion != "" {
+		result.Packages = filterMinVersion(result.Packages, params.MinVersion)
+	}
+	sortPackages(result.Packages)
+	if params.LatestOnly {
+		result.Packages = latestPackages(result.Packages)
+	}
+	return nil, result, nil
+}
 
-	var re *regexp.Regexp
-	if params.Regexp {
-		re, err = regexp.Compile(params.Pattern)
-		if err != nil {
-			return nil, nil, fmt.Errorf("invalid regexp pattern: %w", err)
-		}
+// evr returns the [epoch:]version[-release] of a package.
+func (pack rpm_pack) evr() string {
+	if pack.Epoch != "" {
+		return pack.Epoch + ":" + pack.Version
 	}
+	return pack.Version
+}
 
-	result := SearchPackagesResult{}
-	scanner := bufio.NewScanner(gz)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if !strings.HasSuffix(line, ".rpm") {
-			continue
+// sortPackages sorts the packages by name and the highest version first.
+func sortPackages(packages []rpm_pack) {
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
 		}
-		rpmFile := filepath.Base(line)
+		return compareEVR(packages[i].evr(), packages[j].evr()) > 0
+	})
+}
 
-		actualPackage := parseRPMFileName(rpmFile)
-		if actualPackage.Name == "" {
+// latestPackages keeps only the highest version of every package of the
+// sorted packages. All architectures of the highest version are kept.
+func latestPackages(packages []rpm_pack) []rpm_pack {
+	latest := []rpm_pack{}
+	for i, pack := range packages {
+		if i > 0 && pack.Name == latest[len(latest)-1].Name && compareEVR(pack.evr(), latest[len(latest)-1].evr()) < 0 {
 			continue
 		}
+		latest = append(latest, pack)
+	}
+	return latest
+}
 
-		match := false
-		if params.Pattern == "" {
-			match = true
-		} else if params.Regexp {
-			if re.MatchString(actualPackage.Name) {
-				match = true
-			}
-		} else if params.ExactMatch {
-			if actualPackage.Name == params.Pattern {
-				match = true
-			}
-		} else {
-			if strings.Contains(actualPackage.Name, params.Pattern) {
-				match = true
-			}
-		}
-
-		if match {
-			result.Packages = append(result.Packages, actualPackage)
+// filterMinVersion marks the packages if they satisfy minVersion and drops the
+// ones which don't. If no package satisfies minVersion all packages are kept,
+// so that the available versions are visible.
+func filterMinVersion(packages []rpm_pack, minVersion string) []rpm_pack {
+	var satisfying []rpm_pack
+	for i := range packages {
+		satisfies := compareEVR(packages[i].evr(), minVersion) >= 0
+		packages[i].Satisfies = &satisfies
+		if satisfies {
+			satisfying = append(satisfying, packages[i])
 		}
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error reading gzipped index: %w", err)
+	if len(satisfying) == 0 {
+		return packages
 	}
-	return nil, result, nil
+	return satisfying
 }
@@ -1,8 +1,17 @@
 package osc
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseRPMFileName(t *testing.T) {
@@ -76,6 +85,31 @@ func TestParseRPMFileName(t *testing.T) {
 			filename: "package-1.0.x86_64.rpm",
 			expected: rpm_pack{Name: "package", Version: "1.0", Arch: "x86_64"},
 		},
+		{
+			name:     "epoch in front of the name",
+			filename: "1:foo-2.0-1.x86_64.rpm",
+			expected: rpm_pack{Name: "foo", Version: "2.0-1", Arch: "x86_64", Epoch: "1"},
+		},
+		{
+			name:     "epoch in front of the version",
+			filename: "foo-bar-12:2.0-1.1.noarch.rpm",
+			expected: rpm_pack{Name: "foo-bar", Version: "2.0-1.1", Arch: "noarch", Epoch: "12"},
+		},
+		{
+			name:     "name starting with digits",
+			filename: "389-ds-2.0.1-1.x86_64.rpm",
+			expected: rpm_pack{Name: "389-ds", Version: "2.0.1-1", Arch: "x86_64"},
+		},
+		{
+			name:     "digits after a dash in the name",
+			filename: "python311-pytest-8-8.3.4-1.2.noarch.rpm",
+			expected: rpm_pack{Name: "python311-pytest-8", Version: "8.3.4-1.2", Arch: "noarch"},
+		},
+		{
+			name:     "source package",
+			filename: "hello-2.12-1.1.src.rpm",
+			expected: rpm_pack{Name: "hello", Version: "2.12-1.1", Arch: "src"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -87,3 +121,225 @@ func TestParseRPMFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLocalSearch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   SearchSrcBundleParam
+		expected bool
+	}{
+		{
+			name:     "empty",
+			params:   SearchSrcBundleParam{},
+			expected: true,
+		},
+		{
+			name:     "local only",
+			params:   SearchSrcBundleParam{Projects: []string{"local"}},
+			expected: true,
+		},
+		{
+			name:     "local with upper case",
+			params:   SearchSrcBundleParam{Projects: []string{"Local"}},
+			expected: true,
+		},
+		{
+			name:     "local with name",
+			params:   SearchSrcBundleParam{Projects: []string{"local"}, Name: "foo"},
+			expected: true,
+		},
+		{
+			name:     "remote name",
+			params:   SearchSrcBundleParam{Name: "foo"},
+			expected: false,
+		},
+		{
+			name:     "remote package named local",
+			params:   SearchSrcBundleParam{Name: "local"},
+			expected: false,
+		},
+		{
+			name:     "remote project",
+			params:   SearchSrcBundleParam{Projects: []string{"openSUSE:Factory"}},
+			expected: false,
+		},
+		{
+			name:     "local and remote project",
+			params:   SearchSrcBundleParam{Projects: []string{"local", "openSUSE:Factory"}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isLocalSearch(tc.params); got != tc.expected {
+				t.Errorf("isLocalSearch(%+v) = %v, want %v", tc.params, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFindLinkingPackages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "linkinfo/@project='openSUSE:Factory' and linkinfo/@package='foo' and (@project='home:a' or @project='home:b')"
+		if r.URL.Path != "/search/package" || r.URL.Query().Get("match") != expected {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `<collection matches="1">
+  <package name="foo" project="home:a"><title>Foo</title><description/><devel project="devel:foo" package="foo"/></package>
+</collection>`)
+	}))
+	defer server.Close()
+
+	cred := OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.FindLinkingPackages(context.Background(), nil, FindLinkingPackagesParam{
+		ProjectName: "openSUSE:Factory",
+		PackageName: "foo",
+		Projects:    []string{"home:a", "home:b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []BundleInfo{{Name: "foo", Project: "home:a", Title: "Foo", DevelProject: "devel:foo", WebURL: server.URL + "/package/show/home:a/foo"}}
+	if !reflect.DeepEqual(result.Result, expected) {
+		t.Errorf("expected %+v but got %+v", expected, result.Result)
+	}
+}
+
+func TestFetchIndex(t *testing.T) {
+	downloads := 0
+	revalidations := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "" {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		fmt.Fprint(w, "index")
+	}))
+	defer server.Close()
+
+	cred := OSCCredentials{IndexTTL: time.Hour}
+	cacheFile := filepath.Join(t.TempDir(), "INDEX.gz")
+	for i := 0; i < 2; i++ {
+		if err := cred.fetchIndex(context.Background(), server.URL, cacheFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if downloads != 1 || revalidations != 0 {
+		t.Errorf("expected one download within the ttl, got %d downloads and %d revalidations", downloads, revalidations)
+	}
+
+	past := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(cacheFile, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := cred.fetchIndex(context.Background(), server.URL, cacheFile); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 1 || revalidations != 1 {
+		t.Errorf("expected a revalidation of the expired index, got %d downloads and %d revalidations", downloads, revalidations)
+	}
+	content, err := os.ReadFile(cacheFile)
+	if err != nil || string(content) != "index" {
+		t.Errorf("expected the cached index to be reused, got %q, %v", content, err)
+	}
+	if info, err := os.Stat(cacheFile); err != nil || time.Since(info.ModTime()) > time.Minute {
+		t.Errorf("expected the revalidated index to be refreshed")
+	}
+}
+
+func TestMatchIndex(t *testing.T) {
+	index := `./x86_64/foo-1.0-1.1.x86_64.rpm
+./aarch64/foo-1.0-1.1.aarch64.rpm
+./ppc64le/foo-1.0-1.1.ppc64le.rpm
+./noarch/foo-doc-1.0-1.1.noarch.rpm
+./x86_64/foo-1.0-1.1.x86_64.rpm
+./src/foo-1.0-1.1.src.rpm
+./x86_64/bar-2.0-1.1.x86_64.rpm
+`
+	params := SearchPackagesParams{Pattern: "foo", Arches: []string{"x86_64", "aarch64"}}
+	match, _ := nameMatcher(params)
+	packages, err := matchIndex(strings.NewReader(index), "/repositories/openSUSE:/Factory/standard", params, match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []rpm_pack{
+		{Name: "foo", Version: "1.0-1.1", Arch: "x86_64", Repository: "/repositories/openSUSE:/Factory/standard/x86_64"},
+		{Name: "foo", Version: "1.0-1.1", Arch: "aarch64", Repository: "/repositories/openSUSE:/Factory/standard/aarch64"},
+		{Name: "foo-doc", Version: "1.0-1.1", Arch: "noarch", Repository: "/repositories/openSUSE:/Factory/standard/noarch"},
+	}
+	if !reflect.DeepEqual(packages, expected) {
+		t.Errorf("expected %+v but got %+v", expected, packages)
+	}
+
+	params = SearchPackagesParams{Pattern: "foo", ExactMatch: true}
+	match, _ = nameMatcher(params)
+	packages, err = matchIndex(strings.NewReader(index), "/repositories/openSUSE:/Factory/standard", params, match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 4 {
+		t.Errorf("expected the packages of all architectures without duplicates, got %+v", packages)
+	}
+}
+
+func TestFetchIndexNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing/INDEX.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cred := OSCCredentials{}
+	err := cred.fetchIndex(context.Background(), server.URL+"/missing/INDEX.gz", filepath.Join(t.TempDir(), "INDEX.gz"))
+	if !errors.Is(err, ErrIndexNotFound) {
+		t.Errorf("expected ErrIndexNotFound, got %v", err)
+	}
+	err = cred.fetchIndex(context.Background(), server.URL+"/broken/INDEX.gz", filepath.Join(t.TempDir(), "INDEX.gz"))
+	if err == nil || errors.Is(err, ErrIndexNotFound) {
+		t.Errorf("expected a hard error for a server error, got %v", err)
+	}
+}
+
+func TestPageBundles(t *testing.T) {
+	bundles := make([]BundleInfo, 250)
+	for i := range bundles {
+		bundles[i].Name = fmt.Sprintf("lib%d", i)
+	}
+	if page := pageBundles(bundles, 0, 0); len(page) != defaultSearchLimit || page[0].Name != "lib0" {
+		t.Errorf("expected the first %d bundles, got %d", defaultSearchLimit, len(page))
+	}
+	if page := pageBundles(bundles, 100, 200); len(page) != 50 || page[0].Name != "lib200" {
+		t.Errorf("expected the last 50 bundles, got %d", len(page))
+	}
+	if page := pageBundles(bundles, 10, 300); len(page) != 0 {
+		t.Errorf("expected no bundles after the end, got %d", len(page))
+	}
+}
+
+func TestSrcBundleMatch(t *testing.T) {
+	testCases := []struct {
+		params   SearchSrcBundleParam
+		expected string
+	}{
+		{SearchSrcBundleParam{Name: "foo"}, "@name='foo'"},
+		{SearchSrcBundleParam{Maintainer: "user"}, "person[@userid='user' and @role='maintainer']"},
+		{
+			SearchSrcBundleParam{Name: "foo", Projects: []string{"home:a", "home:b"}, Maintainer: "user"},
+			"@name='foo' and (@project='home:a' or @project='home:b') and person[@userid='user' and @role='maintainer']",
+		},
+	}
+	for _, tc := range testCases {
+		if actual := srcBundleMatch(tc.params); actual != tc.expected {
+			t.Errorf("expected %q but got %q", tc.expected, actual)
+		}
+	}
+	if isLocalSearch(SearchSrcBundleParam{Maintainer: "user"}) {
+		t.Errorf("a search for a maintainer must not be local")
+	}
+}
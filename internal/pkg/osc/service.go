@@ -0,0 +1,152 @@
+package osc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultServiceTimeout = 300
+
+// serviceInfoPollInterval is the time between two polls of the _serviceinfo
+// while waiting for a service run.
+var serviceInfoPollInterval = 2 * time.Second
+
+// ServiceInfo is the state of the server side services as reported in the
+// _serviceinfo of a package.
+type ServiceInfo struct {
+	XMLName xml.Name `xml:"serviceinfo" json:"-"`
+	Code    string   `xml:"code,attr" json:"code" jsonschema:"State of the service run: running, succeeded or failed"`
+	XSrcMd5 string   `xml:"xsrcmd5,attr" json:"xsrcmd5,omitempty"`
+	LSrcMd5 string   `xml:"lsrcmd5,attr" json:"lsrcmd5,omitempty"`
+	Error   string   `xml:"error" json:"error,omitempty"`
+}
+
+// getServiceInfo reads the state of the server side services of a package.
+func (cred *OSCCredentials) getServiceInfo(ctx context.Context, projectName, packageName string) (*ServiceInfo, error) {
+	resp, err := cred.apiGetRequest(ctx, fmt.Sprintf("source/%s/%s/_serviceinfo", projectName, packageName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	var info ServiceInfo
+	if err := xml.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse serviceinfo XML: %w", err)
+	}
+	info.Error = strings.TrimSpace(info.Error)
+	return &info, nil
+}
+
+type TriggerServiceRunParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Wait        bool   `json:"wait,omitempty" jsonschema:"Wait until the service run on the server has finished."`
+	Timeout     int    `json:"timeout,omitempty" jsonschema:"Maximal time in seconds to wait for the service run. Defaults to 300."`
+}
+
+type TriggerServiceRunResult struct {
+	ProjectName string `json:"project_name"`
+	PackageName string `json:"package_name"`
+	Code        string `json:"code" jsonschema:"State of the service run: running, succeeded or failed"`
+	Error       string `json:"error,omitempty"`
+	Success     bool   `json:"success"`
+}
+
+// TriggerServiceRun runs the services of a package on the server, which is
+// needed for services in trigger mode.
+func (cred *OSCCredentials) TriggerServiceRun(ctx context.Context, req *mcp.CallToolRequest, params TriggerServiceRunParam) (*mcp.CallToolResult, *TriggerServiceRunResult, error) {
+	slog.Debug("mcp tool call: TriggerServiceRun", "params", params)
+	ctx, done := cred.startOperation(ctx, req, "trigger_service_run")
+	defer done()
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name must be specified")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name must be specified")
+	}
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = defaultServiceTimeout
+	}
+
+	apiURL := fmt.Sprintf("%s/source/%s/%s?cmd=runservice", cred.GetAPiAddr(), params.ProjectName, params.PackageName)
+	httpReq, err := cred.buildRequest(ctx, "POST", apiURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := cred.do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrBundleOrProjectNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request failed with status: %s\nbody:\n%s", resp.Status, string(body))
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		info, err := cred.getServiceInfo(ctx, params.ProjectName, params.PackageName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get service state: %w", err)
+		}
+		result := &TriggerServiceRunResult{
+			ProjectName: params.ProjectName,
+			PackageName: params.PackageName,
+			Code:        info.Code,
+			Error:       info.Error,
+			Success:     info.Code != "failed",
+		}
+		if !params.Wait || info.Code != "running" {
+			return nil, result, nil
+		}
+		if time.Now().After(deadline) {
+			result.Error = fmt.Sprintf("service run did not finish within %d seconds", timeout)
+			return nil, result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(serviceInfoPollInterval):
+		}
+	}
+}
+
+type GetServiceInfoParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+}
+
+func (cred *OSCCredentials) GetServiceInfo(ctx context.Context, req *mcp.CallToolRequest, params GetServiceInfoParam) (*mcp.CallToolResult, *ServiceInfo, error) {
+	slog.Debug("mcp tool call: GetServiceInfo", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name must be specified")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name must be specified")
+	}
+	info, err := cred.getServiceInfo(ctx, params.ProjectName, params.PackageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, info, nil
+}
@@ -0,0 +1,144 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const servicePrefix = "_service:"
+
+type ListServiceFilesParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+}
+
+type ServiceFile struct {
+	Name     string `json:"name"`
+	Service  string `json:"service"`
+	BaseName string `json:"base_name"`
+	Remote   bool   `json:"remote,omitempty"`
+	Local    bool   `json:"local,omitempty"`
+	Status   string `json:"status" jsonschema:"ok if the file matches its counterpart, stale if the content differs, missing if there is no counterpart"`
+}
+
+type ListServiceFilesResult struct {
+	ServiceGenerated []ServiceFile `json:"service_generated"`
+	Sources          []string      `json:"sources"`
+	Note             string        `json:"note,omitempty"`
+}
+
+// splitServiceFileName splits a name like _service:download_files:foo.tar.gz
+// into the service and the file name.
+func splitServiceFileName(name string) (service, baseName string, ok bool) {
+	if !strings.HasPrefix(name, servicePrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(name, servicePrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", parts[0], true
+	}
+	return parts[0], parts[1], true
+}
+
+func (cred *OSCCredentials) ListServiceFiles(ctx context.Context, req *mcp.CallToolRequest, params ListServiceFilesParam) (*mcp.CallToolResult, *ListServiceFilesResult, error) {
+	slog.Debug("mcp tool call: ListServiceFiles", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+
+	// file name to md5 sum
+	remote := make(map[string]string)
+	remoteFiles, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+	if err != nil && !errors.Is(err, ErrBundleOrProjectNotFound) {
+		return nil, nil, err
+	}
+	for _, rf := range remoteFiles {
+		remote[rf.Name] = rf.MD5
+	}
+	local := make(map[string]string)
+	packagePath := filepath.Join(cred.TempDir, params.ProjectName, params.PackageName)
+	entries, err := os.ReadDir(packagePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read local package directory %s: %w", packagePath, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		hash, err := fileMD5(filepath.Join(packagePath, entry.Name()))
+		if err != nil {
+			continue
+		}
+		local[entry.Name()] = hash
+	}
+
+	if len(remote) == 0 && len(local) == 0 {
+		return nil, nil, ErrBundleOrProjectNotFound
+	}
+	result := &ListServiceFilesResult{
+		ServiceGenerated: []ServiceFile{},
+		Sources:          []string{},
+	}
+
+	names := make([]string, 0, len(remote)+len(local))
+	for name := range remote {
+		names = append(names, name)
+	}
+	for name := range local {
+		if _, ok := remote[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		remoteMd5, isRemote := remote[name]
+		localMd5, isLocal := local[name]
+		service, baseName, ok := splitServiceFileName(name)
+		if !ok {
+			result.Sources = append(result.Sources, name)
+			continue
+		}
+		file := ServiceFile{
+			Name:     name,
+			Service:  service,
+			BaseName: baseName,
+			Remote:   isRemote,
+			Local:    isLocal,
+			Status:   "ok",
+		}
+		// the counterpart of a service file is the file without the prefix in
+		// the working copy, or the service file itself if it was checked out
+		md5 := remoteMd5
+		if !isRemote {
+			md5 = localMd5
+		}
+		counterpart, exists := local[baseName]
+		if !exists {
+			counterpart, exists = localMd5, isLocal
+		}
+		switch {
+		case !exists:
+			file.Status = "missing"
+		case counterpart != md5:
+			file.Status = "stale"
+		}
+		result.ServiceGenerated = append(result.ServiceGenerated, file)
+	}
+	if slices.ContainsFunc(result.ServiceGenerated, func(f ServiceFile) bool { return f.Status != "ok" }) {
+		result.Note = "Service generated files are not uploaded by commit, the remote versions are kept. Run the services again to update them."
+	}
+	return nil, result, nil
+}
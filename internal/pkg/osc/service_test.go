@@ -0,0 +1,90 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerServiceRun(t *testing.T) {
+	oldInterval := serviceInfoPollInterval
+	serviceInfoPollInterval = time.Millisecond
+	defer func() { serviceInfoPollInterval = oldInterval }()
+
+	polls := 0
+	triggered := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/source/home:testuser/testpackage" && r.URL.Query().Get("cmd") == "runservice":
+			triggered = true
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<status code="ok"/>`)
+		case r.Method == "GET" && r.URL.Path == "/source/home:testuser/testpackage/_serviceinfo":
+			polls++
+			w.WriteHeader(http.StatusOK)
+			if polls < 3 {
+				fmt.Fprint(w, `<serviceinfo code="running"/>`)
+			} else {
+				fmt.Fprint(w, `<serviceinfo code="failed" xsrcmd5="abc"><error>service download_files failed:
+  404 not found
+</error></serviceinfo>`)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, result, err := cred.TriggerServiceRun(context.Background(), nil, TriggerServiceRunParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+		Wait:        true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, triggered)
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, "failed", result.Code)
+	assert.False(t, result.Success)
+	assert.Equal(t, "service download_files failed:\n  404 not found", result.Error)
+
+	polls = 0
+	_, result, err = cred.TriggerServiceRun(context.Background(), nil, TriggerServiceRunParam{
+		ProjectName: "home:testuser",
+		PackageName: "testpackage",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, polls)
+	assert.Equal(t, "running", result.Code)
+	assert.True(t, result.Success)
+}
+
+func TestGetServiceInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source/home:testuser/testpackage/_serviceinfo":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `<serviceinfo code="succeeded" xsrcmd5="abc" lsrcmd5="def"/>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cred := &OSCCredentials{Name: "testuser", Passwd: "testpassword", Apiaddr: server.URL}
+	_, info, err := cred.GetServiceInfo(context.Background(), nil, GetServiceInfoParam{ProjectName: "home:testuser", PackageName: "testpackage"})
+	assert.NoError(t, err)
+	assert.Equal(t, "succeeded", info.Code)
+	assert.Equal(t, "abc", info.XSrcMd5)
+	assert.Equal(t, "def", info.LSrcMd5)
+	assert.Empty(t, info.Error)
+
+	_, _, err = cred.GetServiceInfo(context.Background(), nil, GetServiceInfoParam{ProjectName: "home:testuser", PackageName: "missing"})
+	assert.ErrorIs(t, err, ErrBundleOrProjectNotFound)
+}
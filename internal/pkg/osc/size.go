@@ -0,0 +1,116 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultTopFiles = 10
+
+type SourceSizeParam struct {
+	ProjectName   string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName   string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Top           int    `json:"top,omitempty" jsonschema:"Number of largest files to return. Defaults to 10."`
+	CompareRemote bool   `json:"compare_remote,omitempty" jsonschema:"Also return the size of the files in the remote bundle."`
+}
+
+type FileSize struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	RemoteSize *int64 `json:"remote_size,omitempty"`
+}
+
+type SourceSizeResult struct {
+	ProjectName string     `json:"project_name"`
+	PackageName string     `json:"package_name"`
+	TotalSize   int64      `json:"total_size"`
+	NrFiles     int        `json:"nr_files"`
+	RemoteSize  *int64     `json:"remote_total_size,omitempty"`
+	Largest     []FileSize `json:"largest_files"`
+}
+
+func (cred *OSCCredentials) SourceSize(ctx context.Context, req *mcp.CallToolRequest, params SourceSizeParam) (*mcp.CallToolResult, *SourceSizeResult, error) {
+	slog.Debug("mcp tool call: SourceSize", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	top := params.Top
+	if top <= 0 {
+		top = defaultTopFiles
+	}
+
+	packagePath := filepath.Join(cred.TempDir, params.ProjectName, params.PackageName)
+	result := &SourceSizeResult{
+		ProjectName: params.ProjectName,
+		PackageName: params.PackageName,
+		Largest:     []FileSize{},
+	}
+	var files []FileSize
+	err := filepath.WalkDir(packagePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != packagePath && slices.Contains(IgnoredDirs(), d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(packagePath, path)
+		files = append(files, FileSize{Name: name, Size: info.Size()})
+		result.TotalSize += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read local package directory %s: %w", packagePath, err)
+	}
+	result.NrFiles = len(files)
+
+	if params.CompareRemote {
+		remoteFiles, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+		if err != nil {
+			slog.Warn("failed to get remote file list", "error", err)
+		} else {
+			remoteSizes := make(map[string]int64)
+			var remoteTotal int64
+			for _, rf := range remoteFiles {
+				size, err := strconv.ParseInt(rf.Size, 10, 64)
+				if err != nil {
+					continue
+				}
+				remoteSizes[rf.Name] = size
+				remoteTotal += size
+			}
+			result.RemoteSize = &remoteTotal
+			for i := range files {
+				if size, ok := remoteSizes[files[i].Name]; ok {
+					files[i].RemoteSize = &size
+				}
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if len(files) > top {
+		files = files[:top]
+	}
+	result.Largest = append(result.Largest, files...)
+	return nil, result, nil
+}
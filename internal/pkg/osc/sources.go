@@ -0,0 +1,193 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	specTagRegexp    = regexp.MustCompile(`(?i)^(Name|Version|Release):\s*(\S+)`)
+	specDefineRegexp = regexp.MustCompile(`^%(?:define|global)\s+(\w+)\s+(.*\S)`)
+	specSourceRegexp = regexp.MustCompile(`(?i)^((?:Source|Patch)\d*):\s*(\S+)`)
+	specMacroRegexp  = regexp.MustCompile(`%\{(\??)(\w+)\}|%(\w+)`)
+)
+
+type SpecSource struct {
+	Tag  string `json:"tag"`
+	Raw  string `json:"raw"`
+	File string `json:"file" jsonschema:"File name the entry refers to after macro expansion"`
+}
+
+// expandSpecMacros expands the macros of value which are in macros. Unknown
+// conditional macros expand to nothing, other unknown macros are kept.
+func expandSpecMacros(value string, macros map[string]string) string {
+	for range 10 {
+		expanded := specMacroRegexp.ReplaceAllStringFunc(value, func(m string) string {
+			match := specMacroRegexp.FindStringSubmatch(m)
+			name := match[2] + match[3]
+			if v, ok := macros[name]; ok {
+				return v
+			}
+			if match[1] == "?" {
+				return ""
+			}
+			return m
+		})
+		if expanded == value {
+			break
+		}
+		value = expanded
+	}
+	return value
+}
+
+// sourceFileName returns the file name of a Source or Patch entry. For urls
+// the fragment is used if it names the file, else the last path element.
+func sourceFileName(value string) string {
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" {
+		if u.Fragment != "" {
+			return path.Base(u.Fragment)
+		}
+		return path.Base(u.Path)
+	}
+	return path.Base(value)
+}
+
+// parseSpecSources returns the Source and Patch entries of a spec file.
+func parseSpecSources(spec string) []SpecSource {
+	macros := map[string]string{}
+	var raw []SpecSource
+	for _, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if match := specDefineRegexp.FindStringSubmatch(line); match != nil {
+			macros[match[1]] = expandSpecMacros(match[2], macros)
+		} else if match := specSourceRegexp.FindStringSubmatch(line); match != nil {
+			raw = append(raw, SpecSource{Tag: match[1], Raw: match[2]})
+		} else if match := specTagRegexp.FindStringSubmatch(line); match != nil {
+			name := strings.ToLower(match[1])
+			if _, ok := macros[name]; !ok {
+				macros[name] = expandSpecMacros(match[2], macros)
+			}
+		}
+	}
+	sources := []SpecSource{}
+	for _, s := range raw {
+		s.File = sourceFileName(expandSpecMacros(s.Raw, macros))
+		sources = append(sources, s)
+	}
+	return sources
+}
+
+// isPackagingFile returns true for files of a package which are not expected
+// to be referenced by the spec file.
+func isPackagingFile(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasSuffix(name, ".spec") ||
+		strings.HasSuffix(name, ".changes") || strings.HasSuffix(name, "rpmlintrc")
+}
+
+type CheckSourcesParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle or source package"`
+	Local       bool   `json:"local,omitempty" jsonschema:"Check the local bundle instead of the remote one"`
+	SpecFile    string `json:"spec_file,omitempty" jsonschema:"Spec file to check. Defaults to <package_name>.spec."`
+}
+
+type CheckSourcesResult struct {
+	Sources    []SpecSource `json:"sources"`
+	Missing    []string     `json:"missing" jsonschema:"Files referenced by the spec file which are not in the bundle"`
+	Orphaned   []string     `json:"orphaned" jsonschema:"Files in the bundle which are not referenced by the spec file"`
+	Unresolved []string     `json:"unresolved,omitempty" jsonschema:"Entries with macros which could not be expanded"`
+	Note       string       `json:"note,omitempty"`
+}
+
+// checkSources compares the sources of a spec file with the files of the
+// bundle.
+func checkSources(sources []SpecSource, files []string) *CheckSourcesResult {
+	result := &CheckSourcesResult{
+		Sources:  sources,
+		Missing:  []string{},
+		Orphaned: []string{},
+	}
+	present := map[string]bool{}
+	for _, f := range files {
+		present[f] = true
+	}
+	referenced := map[string]bool{}
+	for _, s := range sources {
+		if strings.Contains(s.File, "%") {
+			result.Unresolved = append(result.Unresolved, s.Raw)
+			continue
+		}
+		referenced[s.File] = true
+		if !present[s.File] {
+			result.Missing = append(result.Missing, s.File)
+		}
+	}
+	for _, f := range files {
+		if !referenced[f] && !isPackagingFile(f) {
+			result.Orphaned = append(result.Orphaned, f)
+		}
+	}
+	sort.Strings(result.Orphaned)
+	if len(result.Missing) > 0 && present["_service"] {
+		result.Note = "the bundle has a _service file, missing files may be created by the source services"
+	}
+	return result
+}
+
+func (cred *OSCCredentials) CheckSources(ctx context.Context, req *mcp.CallToolRequest, params CheckSourcesParam) (*mcp.CallToolResult, *CheckSourcesResult, error) {
+	slog.Debug("mcp tool call: CheckSources", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	specFile := params.SpecFile
+	if specFile == "" {
+		specFile = params.PackageName + ".spec"
+	}
+	if filepath.Base(specFile) != specFile {
+		return nil, nil, fmt.Errorf("invalid spec file name: %s", specFile)
+	}
+
+	var spec []byte
+	var files []string
+	if params.Local {
+		dir := filepath.Join(cred.TempDir, params.ProjectName, params.PackageName)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read local package directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, entry.Name())
+			}
+		}
+		if spec, err = os.ReadFile(filepath.Join(dir, specFile)); err != nil {
+			return nil, nil, fmt.Errorf("failed to read spec file: %w", err)
+		}
+	} else {
+		remoteFiles, err := cred.getRemoteList(ctx, params.ProjectName, params.PackageName)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range remoteFiles {
+			files = append(files, f.Name)
+		}
+		if spec, err = cred.getRemoteFileContent(ctx, params.ProjectName, params.PackageName, specFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to get spec file: %w", err)
+		}
+	}
+	return nil, checkSources(parseSpecSources(string(spec)), files), nil
+}
@@ -0,0 +1,43 @@
+package osc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpecSources(t *testing.T) {
+	spec := `%define short_version 1.2
+%global tarname foo-src
+Name:           foo
+Version:        %{short_version}.3
+Release:        0
+Source0:        https://example.com/foo/archive/v%{version}.tar.gz#/%{name}-%{version}.tar.gz
+Source1:        https://example.com/%{tarname}-%{version}.tar.xz
+Source2:        %{name}.keyring
+Source:         %{?missing}vendor.tar.zst
+Patch0:         fix-build.patch
+Patch1:         %{unknown}.patch
+`
+	assert.Equal(t, []SpecSource{
+		{Tag: "Source0", Raw: "https://example.com/foo/archive/v%{version}.tar.gz#/%{name}-%{version}.tar.gz", File: "foo-1.2.3.tar.gz"},
+		{Tag: "Source1", Raw: "https://example.com/%{tarname}-%{version}.tar.xz", File: "foo-src-1.2.3.tar.xz"},
+		{Tag: "Source2", Raw: "%{name}.keyring", File: "foo.keyring"},
+		{Tag: "Source", Raw: "%{?missing}vendor.tar.zst", File: "vendor.tar.zst"},
+		{Tag: "Patch0", Raw: "fix-build.patch", File: "fix-build.patch"},
+		{Tag: "Patch1", Raw: "%{unknown}.patch", File: "%{unknown}.patch"},
+	}, parseSpecSources(spec))
+}
+
+func TestCheckSources(t *testing.T) {
+	sources := []SpecSource{
+		{Tag: "Source0", File: "foo-1.0.tar.gz"},
+		{Tag: "Patch0", File: "fix.patch"},
+		{Tag: "Patch1", Raw: "%{unknown}.patch", File: "%{unknown}.patch"},
+	}
+	result := checkSources(sources, []string{"foo.spec", "foo.changes", "_service", "fix.patch", "foo-0.9.tar.gz"})
+	assert.Equal(t, []string{"foo-1.0.tar.gz"}, result.Missing)
+	assert.Equal(t, []string{"foo-0.9.tar.gz"}, result.Orphaned)
+	assert.Equal(t, []string{"%{unknown}.patch"}, result.Unresolved)
+	assert.NotEmpty(t, result.Note)
+}
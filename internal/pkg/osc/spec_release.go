@@ -0,0 +1,112 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var releaseLineRegexp = regexp.MustCompile(`(?mi)^(Release:[ \t]*)(\S.*?)[ \t]*$`)
+var lastNumberRegexp = regexp.MustCompile(`(\d+)(\D*)$`)
+
+// obsManagedRelease returns true if the release of a spec file is set by OBS
+// at build time and must not be changed in the spec.
+func obsManagedRelease(release string) bool {
+	return release == "0" || strings.Contains(release, "<CI_CNT>") ||
+		strings.Contains(release, "<B_CNT>") || strings.Contains(release, "autorelease")
+}
+
+// bumpRelease increments the last number of a release before the first
+// macro, so that e.g. "1.2%{?dist}" becomes "1.3%{?dist}".
+func bumpRelease(release string) (string, error) {
+	prefix, suffix := release, ""
+	if i := strings.Index(release, "%"); i >= 0 {
+		prefix, suffix = release[:i], release[i:]
+	}
+	match := lastNumberRegexp.FindStringSubmatchIndex(prefix)
+	if match == nil {
+		return "", fmt.Errorf("release '%s' has no number to increment", release)
+	}
+	number, err := strconv.Atoi(prefix[match[2]:match[3]])
+	if err != nil {
+		return "", fmt.Errorf("invalid release number in '%s': %w", release, err)
+	}
+	return prefix[:match[2]] + strconv.Itoa(number+1) + prefix[match[3]:] + suffix, nil
+}
+
+type BumpReleaseParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project"`
+	PackageName string `json:"package_name" jsonschema:"Name of the bundle"`
+	SpecFile    string `json:"spec_file,omitempty" jsonschema:"Spec file in the local bundle. Defaults to <package_name>.spec."`
+	AddChanges  bool   `json:"add_changes,omitempty" jsonschema:"Add an entry to the changes file of the spec file."`
+	Message     string `json:"message,omitempty" jsonschema:"Text of the changes entry. Defaults to a note about the new release."`
+}
+
+type BumpReleaseResult struct {
+	Path         string `json:"path"`
+	OldRelease   string `json:"old_release"`
+	NewRelease   string `json:"new_release"`
+	ChangesEntry string `json:"changes_entry,omitempty"`
+	Note         string `json:"note,omitempty"`
+}
+
+func (cred *OSCCredentials) BumpRelease(ctx context.Context, req *mcp.CallToolRequest, params BumpReleaseParam) (*mcp.CallToolResult, *BumpReleaseResult, error) {
+	slog.Debug("mcp tool call: BumpRelease", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	if params.PackageName == "" {
+		return nil, nil, fmt.Errorf("package name cannot be empty")
+	}
+	specFile := params.SpecFile
+	if specFile == "" {
+		specFile = params.PackageName + ".spec"
+	}
+	if filepath.Base(specFile) != specFile || !strings.HasSuffix(specFile, ".spec") {
+		return nil, nil, fmt.Errorf("invalid spec file name: %s", specFile)
+	}
+	dir := filepath.Join(cred.TempDir, params.ProjectName, params.PackageName)
+	path := filepath.Join(dir, specFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	match := releaseLineRegexp.FindSubmatchIndex(content)
+	if match == nil {
+		return nil, nil, fmt.Errorf("no Release: line found in %s", path)
+	}
+	oldRelease := string(content[match[4]:match[5]])
+	result := &BumpReleaseResult{Path: path, OldRelease: oldRelease, NewRelease: oldRelease}
+	if obsManagedRelease(oldRelease) {
+		result.Note = "the release is set by OBS at build time, the spec file was not changed"
+		return nil, result, nil
+	}
+	result.NewRelease, err = bumpRelease(oldRelease)
+	if err != nil {
+		return nil, nil, err
+	}
+	newContent := string(content[:match[4]]) + result.NewRelease + string(content[match[5]:])
+	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if params.AddChanges {
+		message := params.Message
+		if message == "" {
+			message = fmt.Sprintf("Bump release to %s", result.NewRelease)
+		}
+		changesFile := strings.TrimSuffix(path, ".spec") + ".changes"
+		result.ChangesEntry = createChangesEntry(message, cred.Name+"-mcpbot", cred.EMail)
+		if err := prependChangesEntry(changesFile, result.ChangesEntry); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nil, result, nil
+}
@@ -0,0 +1,57 @@
+package osc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpRelease(t *testing.T) {
+	for release, expected := range map[string]string{
+		"1":               "2",
+		"9%{?dist}":       "10%{?dist}",
+		"1.2%{?dist}":     "1.3%{?dist}",
+		"0.rc1.3%{?dist}": "0.rc1.4%{?dist}",
+		"2.%{snapshot}.1": "3.%{snapshot}.1",
+	} {
+		bumped, err := bumpRelease(release)
+		assert.NoError(t, err, release)
+		assert.Equal(t, expected, bumped, release)
+	}
+	_, err := bumpRelease("%{release}")
+	assert.Error(t, err)
+}
+
+func TestBumpReleaseTool(t *testing.T) {
+	tempDir := t.TempDir()
+	dir := filepath.Join(tempDir, "home:test", "foo")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	spec := filepath.Join(dir, "foo.spec")
+	assert.NoError(t, os.WriteFile(spec, []byte("Name: foo\nVersion: 1.0\nRelease:        3%{?dist}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "foo.changes"), []byte("old entry\n"), 0644))
+
+	cred := &OSCCredentials{Name: "testuser", EMail: "test@example.com", TempDir: tempDir}
+	_, result, err := cred.BumpRelease(context.Background(), nil, BumpReleaseParam{ProjectName: "home:test", PackageName: "foo", AddChanges: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "3%{?dist}", result.OldRelease)
+	assert.Equal(t, "4%{?dist}", result.NewRelease)
+	content, _ := os.ReadFile(spec)
+	assert.Equal(t, "Name: foo\nVersion: 1.0\nRelease:        4%{?dist}\n", string(content))
+	changes, _ := os.ReadFile(filepath.Join(dir, "foo.changes"))
+	assert.Contains(t, string(changes), "- Bump release to 4%{?dist}")
+	assert.True(t, strings.HasSuffix(string(changes), "old entry\n"))
+
+	assert.NoError(t, os.WriteFile(spec, []byte("Name: foo\nRelease: 0\n"), 0644))
+	_, result, err = cred.BumpRelease(context.Background(), nil, BumpReleaseParam{ProjectName: "home:test", PackageName: "foo"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "0", result.NewRelease)
+	assert.NotEmpty(t, result.Note)
+}
@@ -0,0 +1,95 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListStagingProjectsParam struct {
+	ProjectName string `json:"project_name" jsonschema:"Name of the project with the staging workflow, e.g. openSUSE:Factory"`
+}
+
+type StagedRequest struct {
+	Id      string `json:"id"`
+	Package string `json:"package,omitempty"`
+	Creator string `json:"creator,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type StagingProject struct {
+	Name     string          `json:"name"`
+	State    string          `json:"state,omitempty"`
+	Requests []StagedRequest `json:"requests"`
+}
+
+type ListStagingProjectsResult struct {
+	Project         string           `json:"project"`
+	StagingProjects []StagingProject `json:"staging_projects"`
+	Message         string           `json:"message,omitempty"`
+}
+
+func (cred *OSCCredentials) ListStagingProjects(ctx context.Context, req *mcp.CallToolRequest, params ListStagingProjectsParam) (*mcp.CallToolResult, *ListStagingProjectsResult, error) {
+	slog.Debug("mcp tool call: ListStagingProjects", "params", params)
+	if params.ProjectName == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	result := &ListStagingProjectsResult{
+		Project:         params.ProjectName,
+		StagingProjects: []StagingProject{},
+	}
+
+	resp, err := cred.apiGetRequest(ctx, fmt.Sprintf("staging/%s/staging_projects?requests=1&status=1", params.ProjectName), map[string]string{"Accept": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		slog.Debug("no staging workflow", "project", params.ProjectName, "body", string(body))
+		result.Message = fmt.Sprintf("project %s has no staging workflow configured", params.ProjectName)
+		return nil, result, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("api request failed with status: %s", resp.Status)
+	}
+
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(resp.Body); err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	root := doc.SelectElement("staging_projects")
+	if root == nil {
+		return nil, nil, fmt.Errorf("staging_projects not found in response")
+	}
+	for _, stagingElement := range root.SelectElements("staging_project") {
+		staging := StagingProject{
+			Name:     stagingElement.SelectAttrValue("name", ""),
+			State:    stagingElement.SelectAttrValue("state", ""),
+			Requests: []StagedRequest{},
+		}
+		if staging.State == "" {
+			if state := stagingElement.SelectElement("state"); state != nil {
+				staging.State = strings.TrimSpace(state.Text())
+			}
+		}
+		if staged := stagingElement.SelectElement("staged_requests"); staged != nil {
+			for _, request := range staged.SelectElements("request") {
+				staging.Requests = append(staging.Requests, StagedRequest{
+					Id:      request.SelectAttrValue("id", ""),
+					Package: request.SelectAttrValue("package", ""),
+					Creator: request.SelectAttrValue("creator", ""),
+					Type:    request.SelectAttrValue("type", ""),
+				})
+			}
+		}
+		result.StagingProjects = append(result.StagingProjects, staging)
+	}
+	return nil, result, nil
+}
@@ -76,7 +76,7 @@ func (c *OSCCredentials) GetTools() []ToolDef {
 		},
 		{
 			Name:        "list_requests",
-			Description: fmt.Sprintf("Get a list of requests. Need to set one of the following: user, group, project, package, state, reviewstates, types, ids. If not package group or ids ist set %s will be set for user.", c.Name),
+			Description: fmt.Sprintf("Get a list of requests. Need to set one of the following: user, group, project, package, state, reviewstates, types, ids. If not package group or ids ist set %s will be set for user. Use the mode outgoing, incoming or review to triage the requests of the user.", c.Name),
 			Handler:     c.ListRequests,
 		},
 		{
@@ -84,5 +84,235 @@ func (c *OSCCredentials) GetTools() []ToolDef {
 			Description: "Get a single request by its ID. Includes a diff to what has changed in that request.",
 			Handler:     c.GetRequest,
 		},
+		{
+			Name:        "list_my_groups",
+			Description: fmt.Sprintf("List the groups a user is member of. If no user is given, the groups of %s are listed. Use this to decide if a review assigned to a group can be handled by the user.", c.Name),
+			Handler:     c.ListMyGroups,
+		},
+		{
+			Name:        "list_group_members",
+			Description: "List the members and maintainers of a group.",
+			Handler:     c.ListGroupMembers,
+		},
+		{
+			Name:        "source_diff",
+			Description: "Get the diff between two revisions of a bundle. Links are expanded if possible. If no revisions are given, the latest revision is compared with its predecessor.",
+			Handler:     c.SourceDiff,
+		},
+		{
+			Name:        "create_maintenance_request",
+			Description: "Create a maintenance incident request which starts an update of a package for a maintained distribution. Returns the ID of the new request.",
+			Handler:     c.CreateMaintenanceRequest,
+		},
+		{
+			Name:        "set_review_state",
+			Description: "Accept or decline a pending review of a request. Exactly one of by_user, by_group or by_project must match the pending review. Returns the updated reviews of the request.",
+			Handler:     c.SetReviewState,
+		},
+		{
+			Name:        "resolve_supersede_chain",
+			Description: "Follow the superseded_by links of a request to the current request, which is the one which should be reviewed. Returns the ordered chain of requests.",
+			Handler:     c.ResolveSupersedeChain,
+		},
+		{
+			Name:        "summarize_history",
+			Description: "Summarize the revision comments of a bundle in a revision range as one changes entry. Empty and duplicate comments are dropped. The draft entry can be edited and used as commit message.",
+			Handler:     c.SummarizeHistory,
+		},
+		{
+			Name:        "list_staging_projects",
+			Description: "List the staging projects of a project which uses the staging workflow, like openSUSE:Factory. Returns the state of each staging project and the requests staged in it.",
+			Handler:     c.ListStagingProjects,
+		},
+		{
+			Name:        "add_review",
+			Description: "Add a review by a user, group or project to a request. Returns the reviews of the request.",
+			Handler:     c.AddReviewToRequest,
+		},
+		{
+			Name:        "get_package_history",
+			Description: "Get the revision history of a bundle with the source md5, version, author, time and comment of each revision, oldest first. Use limit to get only the newest revisions. Optionally returns the number of commits per author and the time span of the history.",
+			Handler:     c.GetPackageHistory,
+		},
+		{
+			Name:        "get_build_info",
+			Description: "Get the build environment which OBS computed for a bundle: the build dependencies with their versions, the repository path and which packages are preinstalled. Use this to understand which dependencies a local build pulls in.",
+			Handler:     c.GetBuildInfo,
+		},
+		{
+			Name:        "get_publish_state",
+			Description: "Get the effective publish state of each repository of a project or bundle. Use this if a package was built but not published.",
+			Handler:     c.GetPublishState,
+		},
+		{
+			Name:        "set_publish_state",
+			Description: "Enable or disable publishing for one or all repositories of a project or bundle. The rest of the meta data is kept. Returns the effective publish state of each repository.",
+			Handler:     c.SetPublishState,
+		},
+		{
+			Name:        "get_config",
+			Description: "Show the configuration which is used: the api address, the user, the working directory and the enabled tools. The password is never shown.",
+			Handler:     c.GetConfig,
+		},
+		{
+			Name:        "source_size",
+			Description: "Get the total size of a local bundle and its largest files. Optionally compare with the sizes of the remote bundle. Use this to decide if large archives should rather be downloaded with the download_files service.",
+			Handler:     c.SourceSize,
+		},
+		{
+			Name:        "list_service_files",
+			Description: "List which files of a bundle are generated by source services (prefixed with _service:) and which are plain sources. Service generated files which are missing or differ in the local checkout are flagged. Commit never uploads service generated files.",
+			Handler:     c.ListServiceFiles,
+		},
+		{
+			Name:        "get_raw_meta",
+			Description: "Get the unparsed meta XML of a project or bundle. Use this only if get_project_meta doesn't show the needed information.",
+			Handler:     c.GetRawMeta,
+		},
+		{
+			Name:        "set_raw_meta",
+			Description: "Replace the meta of a project or bundle with the given XML. The XML must be complete, use get_raw_meta to get the current meta first. Prefer set_project_meta for the common settings.",
+			Handler:     c.SetRawMeta,
+		},
+		{
+			Name:        "trigger_service_run",
+			Description: "Run the services of a bundle on the build server. This is needed for services in trigger mode which are not run by run_services. Optionally waits until the service run has finished and reports its state and errors.",
+			Handler:     c.TriggerServiceRun,
+		},
+		{
+			Name:        "get_service_info",
+			Description: "Get the state of the services of a bundle on the build server from its _serviceinfo. The code is running, succeeded or failed, failures come with the error output of the service.",
+			Handler:     c.GetServiceInfo,
+		},
+		{
+			Name:        "apply_copyright_header",
+			Description: "Insert the configured copyright header at the top of a spec or source file of a local bundle, or refresh an existing header with the current year. Copyright lines of other holders are kept.",
+			Handler:     c.ApplyCopyrightHeader,
+		},
+		{
+			Name:        "list_operations",
+			Description: "List the running long operations like builds, checkouts, commits or service runs of this session, which can be cancelled with cancel_operation.",
+			Handler:     c.ListOperations,
+		},
+		{
+			Name:        "cancel_operation",
+			Description: "Cancel a running operation like a build, checkout, commit or service run by the id returned by list_operations.",
+			Handler:     c.CancelOperation,
+		},
+		{
+			Name:        "binary_to_source",
+			Description: "Find the source package which builds a binary package by searching the published binaries. Returns one candidate for each project the binary is built in. Use the result to branch the right bundle for a fix.",
+			Handler:     c.BinaryToSource,
+		},
+		{
+			Name:        "run_rpmlint",
+			Description: "Run rpmlint on a built RPM or a spec file without rebuilding the bundle. Uses the rpmlintrc of the bundle if there is one. Returns the findings and the raw output.",
+			Handler:     c.RunRpmlint,
+		},
+		{
+			Name:        "get_package_build_matrix",
+			Description: "Get the build status of a bundle for all repositories and architectures in one call. Multibuild flavors are included as repository/arch/flavor.",
+			Handler:     c.GetPackageBuildMatrix,
+		},
+		{
+			Name:        "export_project",
+			Description: "Export the meta of a project and the meta of its packages into a single manifest file, which can be used to re-create the project with set_raw_meta. Use filter and limit for big projects.",
+			Handler:     c.ExportProject,
+		},
+		{
+			Name:        "import_project",
+			Description: "Create a project and the meta of its packages from a manifest written by export_project. The sources are not copied. Existing objects are updated or, with skip_existing, left untouched, so the import can be repeated.",
+			Handler:     c.ImportProject,
+		},
+		{
+			Name:        "get_request_action_diff",
+			Description: "Get the diff of a single action of a request by its index. Use this instead of get_request for requests with many actions.",
+			Handler:     c.GetRequestActionDiff,
+		},
+		{
+			Name:        "is_build_clean",
+			Description: "Check if a bundle built successfully in all repositories and architectures before submitting it. Disabled and excluded builds count as clean. Lists the failed and the still pending builds, optionally waits until no build is pending.",
+			Handler:     c.IsBuildClean,
+		},
+		{
+			Name:        "release",
+			Description: "Release the binaries of a project or of a single package into the release targets configured in the project meta or into the given target project and repository.",
+			Handler:     c.Release,
+		},
+		{
+			Name:        "find_linking_packages",
+			Description: "Find the packages which link to the given package, on the whole instance or in the given projects. Use this before changing a package which is linked by other packages.",
+			Handler:     c.FindLinkingPackages,
+		},
+		{
+			Name:        "bump_release",
+			Description: "Increment the Release: of the spec file of a local bundle, keeping macro suffixes like %{?dist}, and optionally add a changes entry. Releases which are set by OBS, like Release: 0, are left alone.",
+			Handler:     c.BumpRelease,
+		},
+		{
+			Name:        "check_sources",
+			Description: "Check that the Source and Patch files of the spec file of a bundle are in the bundle. Reports missing files and files which are not referenced by the spec file. Run this before run_build.",
+			Handler:     c.CheckSources,
+		},
+		{
+			Name:        "change_request_state",
+			Description: "Accept, decline or revoke a request. Returns the request with its new state.",
+			Handler:     c.ChangeRequestState,
+		},
+		{
+			Name:        "create_submit_request",
+			Description: "Create a submit request to merge the changes of a package, usually of a branch, into the target project. Returns the ID of the new request.",
+			Handler:     c.CreateSubmitRequest,
+		},
+		{
+			Name:        "get_package_meta",
+			Description: "Get the meta of a bundle: title, description, devel project and the build, publish and useforbuild flags of every repository of the project.",
+			Handler:     c.GetPackageMeta,
+		},
+		{
+			Name:        "set_package_meta",
+			Description: "Set the title, description, devel project or the build, publish and useforbuild flags of a bundle. Only the given fields are changed, the bundle is created if it does not exist.",
+			Handler:     c.SetPackageMeta,
+		},
+		{
+			Name:        "get_project_config",
+			Description: "Get the build configuration (prjconf) of a project as raw text. The prjconf defines the macros and the Required, Prefer and Substitute rules of the builds.",
+			Handler:     c.GetProjectConfig,
+		},
+		{
+			Name:        "set_project_config",
+			Description: "Replace the build configuration (prjconf) of a project with raw text, e.g. to add a Macros: section or Prefer: lines to fix a build. Read the config with get_project_config first, the complete config must be given.",
+			Handler:     c.SetProjectConfig,
+		},
+		{
+			Name:        "trigger_rebuild",
+			Description: "Trigger a rebuild of a project or bundle on the build server without changing the sources, e.g. after an unresolvable dependency was fixed. Can be limited to a repository and an architecture.",
+			Handler:     c.TriggerRebuild,
+		},
+		{
+			Name:        "list_binaries",
+			Description: "List the binaries of the last successful build of a bundle on the build server for a repository and architecture, e.g. the built rpms with their sizes.",
+			Handler:     c.ListBuildResults,
+		},
+		{
+			Name:        "download_binary",
+			Description: "Download a binary of a build, e.g. an rpm listed by list_binaries, into the local work directory so that it can be inspected.",
+			Handler:     c.DownloadBinary,
+		},
+		{
+			Name:        "what_depends_on",
+			Description: "List the bundles of a repository which need the given bundle to build, e.g. to find out what has to be rebuilt or tested when a core library is changed.",
+			Handler:     c.WhatDependsOn,
+		},
+		{
+			Name:        "get_owner",
+			Description: "Find the maintainers and bugowners of a binary package or bundle. Use it to find out who to ask or where to submit a change to another package.",
+			Handler:     c.GetOwner,
+		},
+		{
+			Name:        "delete_project",
+			Description: "Deletes a remote project and all the packages of this project. Ask the user before deleting a project and set confirm to true.",
+			Handler:     c.DeleteProject,
+		},
 	}
 }
@@ -35,14 +35,21 @@ func main() {
 	pflag.String("user", "", "OBS username")
 	pflag.String("email", "", "user's email address")
 	pflag.String("password", "", "OBS password")
+	pflag.String("token", "", "OBS personal access token, which is used instead of the password")
 	pflag.Bool("print-creds", false, "Just print the retrieved credentials and exit")
 	pflag.Bool("clean-workdir", false, "Cleans the workdir before usage")
+	pflag.Int("max-log-lines", 0, "Maximal number of build log lines returned at once, defaults to 1000")
+	pflag.Int("concurrency", 0, "Maximal number of parallel requests to the build service for a single tool call, defaults to 4")
+	pflag.Int("max-upload-concurrency", 0, "Maximal number of parallel file uploads of a commit, defaults to 4")
+	pflag.Duration("http-timeout", 0, "Timeout of a single request to the build service including the transfer of files, defaults to 10m, a negative value disables it")
+	pflag.Duration("index-ttl", 0, "Time after which the cached package index of a repository is checked for updates, defaults to 1h")
+	pflag.Int("retries", 0, "Number of retries of uploads and commits which failed with a server or network error, defaults to 3, a negative value disables them")
 	pflag.String("logfile", "", "if set, log to this file instead of stderr")
 	pflag.BoolP("verbose", "v", false, "Enable verbose logging")
 	pflag.BoolP("debug", "d", false, "Enable debug logging")
 	pflag.Bool("log-json", false, "Output logs in JSON format (machine-readable)")
 	pflag.Bool("list-tools", false, "List all available tools and exit")
-	pflag.StringSlice("enabled-tools", nil, "A list of tools to enable. Defaults to all tools.")
+	pflag.StringSlice("enabled-tools", nil, "A list of tools to enable. Defaults to all tools except delete_project, which has to be enabled explicitly.")
 
 	pflag.Parse()
 	viper.SetEnvPrefix("OSC_MCP")
@@ -113,7 +120,7 @@ func main() {
 		os.Exit(1)
 	}
 	if viper.GetBool("print-creds") {
-		fmt.Printf("user: %s\npasswd: %s\napi: %s\n", obsCred.Name, obsCred.Passwd, obsCred.Apiaddr)
+		fmt.Printf("user: %s\npasswd: %s\ntoken: %s\napi: %s\n", obsCred.Name, obsCred.Passwd, obsCred.Token, obsCred.Apiaddr)
 		os.Exit(0)
 	}
 
@@ -126,6 +133,8 @@ func main() {
 	tools := []struct {
 		Tool     *mcp.Tool
 		Register func(server *mcp.Server, tool *mcp.Tool)
+		// OptIn tools are destructive and only enabled with --enabled-tools
+		OptIn bool
 	}{
 		{
 			Tool: &mcp.Tool{
@@ -201,12 +210,16 @@ func main() {
 				mcp.AddTool(server, tool, obsCred.Create)
 			},
 		},
-		/*
-			mcp.AddTool(server, &mcp.Tool{
+		{
+			Tool: &mcp.Tool{
 				Name:        "delete_project",
-				Description: "Deletes a remote project and all the packages of this project.",
-			}, obsCred.DeleteProject)
-		*/
+				Description: "Deletes a remote project and all the packages of this project. Ask the user before deleting a project and set confirm to true.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.DeleteProject)
+			},
+			OptIn: true,
+		},
 		{
 			Tool: &mcp.Tool{
 				Name:        "checkout_bundle",
@@ -247,7 +260,7 @@ func main() {
 		{
 			Tool: &mcp.Tool{
 				Name:        "list_requests",
-				Description: fmt.Sprintf("Get a list of requests. Need to set one of the following: user, group, project, package, state, reviewstates, types, ids. If not package group or ids ist set %s will be set for user.", obsCred.Name),
+				Description: fmt.Sprintf("Get a list of requests. Need to set one of the following: user, group, project, package, state, reviewstates, types, ids. If not package group or ids ist set %s will be set for user. Use the mode outgoing, incoming or review to triage the requests of the user.", obsCred.Name),
 				InputSchema: osc.ListRequestsInputSchema(),
 			},
 			Register: func(server *mcp.Server, tool *mcp.Tool) {
@@ -263,6 +276,411 @@ func main() {
 				mcp.AddTool(server, tool, obsCred.GetRequest)
 			},
 		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_my_groups",
+				Description: fmt.Sprintf("List the groups a user is member of. If no user is given, the groups of %s are listed. Use this to decide if a review assigned to a group can be handled by the user.", obsCred.Name),
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListMyGroups)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_group_members",
+				Description: "List the members and maintainers of a group.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListGroupMembers)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "source_diff",
+				Description: "Get the diff between two revisions of a bundle. Links are expanded if possible. If no revisions are given, the latest revision is compared with its predecessor.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SourceDiff)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "create_maintenance_request",
+				Description: "Create a maintenance incident request which starts an update of a package for a maintained distribution. Returns the ID of the new request.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.CreateMaintenanceRequest)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "set_review_state",
+				Description: "Accept or decline a pending review of a request. Exactly one of by_user, by_group or by_project must match the pending review. Returns the updated reviews of the request.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SetReviewState)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "resolve_supersede_chain",
+				Description: "Follow the superseded_by links of a request to the current request, which is the one which should be reviewed. Returns the ordered chain of requests.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ResolveSupersedeChain)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "summarize_history",
+				Description: "Summarize the revision comments of a bundle in a revision range as one changes entry. Empty and duplicate comments are dropped. The draft entry can be edited and used as commit message.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SummarizeHistory)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_staging_projects",
+				Description: "List the staging projects of a project which uses the staging workflow, like openSUSE:Factory. Returns the state of each staging project and the requests staged in it.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListStagingProjects)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "add_review",
+				Description: "Add a review by a user, group or project to a request. Returns the reviews of the request.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.AddReviewToRequest)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_package_history",
+				Description: "Get the revision history of a bundle with the source md5, version, author, time and comment of each revision, oldest first. Use limit to get only the newest revisions. Optionally returns the number of commits per author and the time span of the history.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetPackageHistory)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_build_info",
+				Description: "Get the build environment which OBS computed for a bundle: the build dependencies with their versions, the repository path and which packages are preinstalled. Use this to understand which dependencies a local build pulls in.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetBuildInfo)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_publish_state",
+				Description: "Get the effective publish state of each repository of a project or bundle. Use this if a package was built but not published.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetPublishState)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "set_publish_state",
+				Description: "Enable or disable publishing for one or all repositories of a project or bundle. The rest of the meta data is kept. Returns the effective publish state of each repository.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SetPublishState)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_config",
+				Description: "Show the configuration which is used: the api address, the user, the working directory and the enabled tools. The password is never shown.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetConfig)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "source_size",
+				Description: "Get the total size of a local bundle and its largest files. Optionally compare with the sizes of the remote bundle. Use this to decide if large archives should rather be downloaded with the download_files service.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SourceSize)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_service_files",
+				Description: "List which files of a bundle are generated by source services (prefixed with _service:) and which are plain sources. Service generated files which are missing or differ in the local checkout are flagged. Commit never uploads service generated files.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListServiceFiles)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_raw_meta",
+				Description: "Get the unparsed meta XML of a project or bundle. Use this only if get_project_meta doesn't show the needed information.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetRawMeta)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "set_raw_meta",
+				Description: "Replace the meta of a project or bundle with the given XML. The XML must be complete, use get_raw_meta to get the current meta first. Prefer set_project_meta for the common settings.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SetRawMeta)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "trigger_service_run",
+				Description: "Run the services of a bundle on the build server. This is needed for services in trigger mode which are not run by run_services. Optionally waits until the service run has finished and reports its state and errors.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.TriggerServiceRun)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_service_info",
+				Description: "Get the state of the services of a bundle on the build server from its _serviceinfo. The code is running, succeeded or failed, failures come with the error output of the service.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetServiceInfo)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "apply_copyright_header",
+				Description: "Insert the configured copyright header at the top of a spec or source file of a local bundle, or refresh an existing header with the current year. Copyright lines of other holders are kept.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ApplyCopyrightHeader)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_operations",
+				Description: "List the running long operations like builds, checkouts, commits or service runs of this session, which can be cancelled with cancel_operation.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListOperations)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "cancel_operation",
+				Description: "Cancel a running operation like a build, checkout, commit or service run by the id returned by list_operations.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.CancelOperation)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "binary_to_source",
+				Description: "Find the source package which builds a binary package by searching the published binaries. Returns one candidate for each project the binary is built in. Use the result to branch the right bundle for a fix.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.BinaryToSource)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "run_rpmlint",
+				Description: "Run rpmlint on a built RPM or a spec file without rebuilding the bundle. Uses the rpmlintrc of the bundle if there is one. Returns the findings and the raw output.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.RunRpmlint)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_package_build_matrix",
+				Description: "Get the build status of a bundle for all repositories and architectures in one call. Multibuild flavors are included as repository/arch/flavor.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetPackageBuildMatrix)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "export_project",
+				Description: "Export the meta of a project and the meta of its packages into a single manifest file, which can be used to re-create the project with set_raw_meta. Use filter and limit for big projects.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ExportProject)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "import_project",
+				Description: "Create a project and the meta of its packages from a manifest written by export_project. The sources are not copied. Existing objects are updated or, with skip_existing, left untouched, so the import can be repeated.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ImportProject)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_request_action_diff",
+				Description: "Get the diff of a single action of a request by its index. Use this instead of get_request for requests with many actions.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetRequestActionDiff)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "is_build_clean",
+				Description: "Check if a bundle built successfully in all repositories and architectures before submitting it. Disabled and excluded builds count as clean. Lists the failed and the still pending builds, optionally waits until no build is pending.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.IsBuildClean)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "release",
+				Description: "Release the binaries of a project or of a single package into the release targets configured in the project meta or into the given target project and repository.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.Release)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "find_linking_packages",
+				Description: "Find the packages which link to the given package, on the whole instance or in the given projects. Use this before changing a package which is linked by other packages.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.FindLinkingPackages)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "bump_release",
+				Description: "Increment the Release: of the spec file of a local bundle, keeping macro suffixes like %{?dist}, and optionally add a changes entry. Releases which are set by OBS, like Release: 0, are left alone.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.BumpRelease)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "check_sources",
+				Description: "Check that the Source and Patch files of the spec file of a bundle are in the bundle. Reports missing files and files which are not referenced by the spec file. Run this before run_build.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.CheckSources)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "change_request_state",
+				Description: "Accept, decline or revoke a request. Returns the request with its new state.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ChangeRequestState)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "create_submit_request",
+				Description: "Create a submit request to merge the changes of a package, usually of a branch, into the target project. Returns the ID of the new request.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.CreateSubmitRequest)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_package_meta",
+				Description: "Get the meta of a bundle: title, description, devel project, scmsync URL and the build, publish and useforbuild flags of every repository of the project.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetPackageMeta)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "set_package_meta",
+				Description: "Set the title, description, devel project, scmsync URL or the build, publish and useforbuild flags of a bundle. Only the given fields are changed, the bundle is created if it does not exist.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SetPackageMeta)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_project_config",
+				Description: "Get the build configuration (prjconf) of a project as raw text. The prjconf defines the macros and the Required, Prefer and Substitute rules of the builds.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetProjectConfig)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "set_project_config",
+				Description: "Replace the build configuration (prjconf) of a project with raw text, e.g. to add a Macros: section or Prefer: lines to fix a build. Read the config with get_project_config first, the complete config must be given.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.SetProjectConfig)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "trigger_rebuild",
+				Description: "Trigger a rebuild of a project or bundle on the build server without changing the sources, e.g. after an unresolvable dependency was fixed. Can be limited to a repository and an architecture.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.TriggerRebuild)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "list_binaries",
+				Description: "List the binaries of the last successful build of a bundle on the build server for a repository and architecture, e.g. the built rpms with their sizes.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.ListBuildResults)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "download_binary",
+				Description: "Download a binary of a build, e.g. an rpm listed by list_binaries, into the local work directory so that it can be inspected.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.DownloadBinary)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "what_depends_on",
+				Description: "List the bundles of a repository which need the given bundle to build, e.g. to find out what has to be rebuilt or tested when a core library is changed.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.WhatDependsOn)
+			},
+		},
+		{
+			Tool: &mcp.Tool{
+				Name:        "get_owner",
+				Description: "Find the maintainers and bugowners of a binary package or bundle. Use it to find out who to ask or where to submit a change to another package.",
+			},
+			Register: func(server *mcp.Server, tool *mcp.Tool) {
+				mcp.AddTool(server, tool, obsCred.GetOwner)
+			},
+		},
 		{
 			Tool: &mcp.Tool{
 				Name:        "list_archive_files",
@@ -292,10 +710,15 @@ func main() {
 	}
 	var enabledTools []string
 	if !pflag.CommandLine.Changed("enabled-tools") {
-		enabledTools = allTools
+		for _, tool := range tools {
+			if !tool.OptIn {
+				enabledTools = append(enabledTools, tool.Tool.Name)
+			}
+		}
 	} else {
 		enabledTools = viper.GetStringSlice("enabled-tools")
 	}
+	obsCred.EnabledTools = enabledTools
 	// register the enabled tools
 	for _, tool := range tools {
 		if slices.Contains(enabledTools, tool.Tool.Name) {
@@ -315,6 +738,10 @@ func main() {
 		Name:        "service_usage",
 		Description: "How to use OBS source services.",
 	}, obsCred.Service)
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "submit_workflow",
+		Description: "Steps to get a change from a commit into the target project with a submit request and reviews.",
+	}, obsCred.PromptSubmitWorkflow)
 	server.AddResource(&mcp.Resource{
 		Name:        "spdx_licenses",
 		MIMEType:    "text/plain",